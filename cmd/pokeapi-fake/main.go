@@ -0,0 +1,79 @@
+// Command pokeapi-fake serves the recorded PokeAPI fixtures on a local port,
+// so the server can run without internet access:
+//
+//	go run ./cmd/pokeapi-fake -addr :8081
+//	POKEAPI_BASE_URL=http://localhost:8081/api/v2 go run ./cmd/server
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokeapifake"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	latency := flag.Duration("latency", 0, "delay added to every response, e.g. 200ms")
+	errorRate := flag.Float64("error-rate", 0, "fraction of requests answered with 500, between 0 and 1")
+	fixtures := flag.String("fixtures", "", "directory of <resource>/<name>.json fixtures instead of the embedded ones")
+	seed := flag.Int64("seed", 0, "random seed for -error-rate, 0 picks one")
+	var fail failFlag
+	flag.Var(&fail, "fail", "answer a path with a status, e.g. pokemon/mew=503 (repeatable)")
+	flag.Parse()
+
+	if *errorRate < 0 || *errorRate > 1 {
+		log.Fatal("-error-rate must be between 0 and 1")
+	}
+
+	opts := pokeapifake.Options{
+		Latency:   *latency,
+		ErrorRate: *errorRate,
+		Errors:    fail,
+		Seed:      *seed,
+	}
+	if *fixtures != "" {
+		opts.Fixtures = os.DirFS(*fixtures)
+	}
+
+	server, err := pokeapifake.New(opts)
+	if err != nil {
+		log.Fatal("Failed to load fixtures:", err)
+	}
+	for _, resource := range pokeapifake.Resources {
+		if names := server.Names(resource); len(names) > 0 {
+			log.Printf("%s: %s", resource, strings.Join(names, ", "))
+		}
+	}
+
+	log.Printf("Fake PokeAPI listening on %s/api/v2", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
+
+// failFlag collects repeated -fail path=status flags
+type failFlag map[string]int
+
+func (f *failFlag) String() string {
+	return fmt.Sprint(map[string]int(*f))
+}
+
+func (f *failFlag) Set(value string) error {
+	path, statusStr, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected path=status, got %q", value)
+	}
+	status, err := strconv.Atoi(statusStr)
+	if err != nil || status < 100 || status > 599 {
+		return fmt.Errorf("invalid status %q", statusStr)
+	}
+	if *f == nil {
+		*f = make(failFlag)
+	}
+	(*f)[path] = status
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+func (a *app) cache(args []string) error {
+	if len(args) == 0 || args[0] != "warm" {
+		return fmt.Errorf("usage: pokectl cache warm [-from 1 -to 151] [name ...]")
+	}
+
+	fs := flag.NewFlagSet("cache warm", flag.ExitOnError)
+	from := fs.Int("from", 0, "first Pokedex number to fetch")
+	to := fs.Int("to", 0, "last Pokedex number to fetch")
+	fs.Parse(args[1:])
+
+	// Warming an in-memory cache would be lost when pokectl exits
+	if a.cfg.PokeAPICacheDir == "" {
+		return fmt.Errorf("POKEAPI_CACHE_DIR must be set to the server's cache directory")
+	}
+	cache, err := services.NewFileCache(a.cfg.PokeAPICacheDir, 0)
+	if err != nil {
+		return err
+	}
+	service := services.NewPokemonService(nil,
+		services.WithCache(cache),
+		services.WithBaseURL(a.cfg.PokeAPIBaseURL),
+	)
+
+	keys := fs.Args()
+	if *from > 0 || *to > 0 {
+		if *from < 1 || *to < *from {
+			return fmt.Errorf("-from and -to must form a range starting at 1 or more")
+		}
+		for id := *from; id <= *to; id++ {
+			keys = append(keys, strconv.Itoa(id))
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("nothing to warm, pass names or -from/-to")
+	}
+
+	var failed []string
+	for _, key := range keys {
+		if err := service.WarmCache(key); err != nil {
+			failed = append(failed, key)
+			fmt.Fprintf(os.Stderr, "%s: %v\n", key, err)
+			continue
+		}
+		if !a.out.json {
+			fmt.Println("cached", key)
+		}
+	}
+
+	warmed := len(keys) - len(failed)
+	if a.out.json {
+		return a.out.printJSON(map[string]interface{}{"warmed": warmed, "failed": failed})
+	}
+	fmt.Printf("\nwarmed %d of %d\n", warmed, len(keys))
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+// exportPageSize is how many search rows are fetched per request while exporting
+const exportPageSize = 1000
+
+func (a *app) export(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: pokectl export users|searches [-format csv|json] [-out file]")
+	}
+	table := args[0]
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "csv", "csv or json")
+	out := fs.String("out", "", "output file (default stdout)")
+	fs.Parse(args[1:])
+
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("-format must be csv or json")
+	}
+
+	var headers []string
+	var rows [][]string
+	var records interface{}
+
+	switch table {
+	case "users":
+		service, err := a.userService()
+		if err != nil {
+			return err
+		}
+		users, err := service.GetAllUsers()
+		if err != nil {
+			return err
+		}
+		headers, rows, records = userHeaders, userRows(users), users
+
+	case "searches":
+		repo, err := a.searchRepo()
+		if err != nil {
+			return err
+		}
+		searches, err := allSearches(repo)
+		if err != nil {
+			return err
+		}
+		headers, rows, records = searchHeaders, searchRows(searches), searches
+
+	default:
+		return fmt.Errorf("unknown table %q, expected users or searches", table)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return err
+		}
+	} else {
+		cw := csv.NewWriter(w)
+		cw.Write(headers)
+		cw.WriteAll(rows)
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "exported %d %s to %s\n", len(rows), table, *out)
+	}
+	return nil
+}
+
+// allSearches walks the search log page by page with the keyset cursor
+func allSearches(repo repository.SearchRepository) ([]repository.PokemonSearch, error) {
+	var all []repository.PokemonSearch
+	var after *repository.Cursor
+	for {
+		page, next, err := repo.ListSearches(after, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if next == nil {
+			return all, nil
+		}
+		after = next
+	}
+}
+
+var searchHeaders = []string{"ID", "POKEMON_NAME", "POKEMON_ID", "FOUND", "SEARCHED_AT"}
+
+func searchRows(searches []repository.PokemonSearch) [][]string {
+	rows := make([][]string, len(searches))
+	for i, s := range searches {
+		pokemonID := ""
+		if s.PokemonID != nil {
+			pokemonID = strconv.Itoa(*s.PokemonID)
+		}
+		rows[i] = []string{
+			strconv.Itoa(s.ID),
+			s.PokemonName,
+			pokemonID,
+			strconv.FormatBool(s.Found),
+			s.SearchedAt,
+		}
+	}
+	return rows
+}
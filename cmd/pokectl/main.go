@@ -0,0 +1,149 @@
+// Command pokectl is an admin CLI for the Pokemon chatbot API. It reuses the
+// server's services and repositories, so it reads the same environment
+// (SUPABASE_URL, SUPABASE_KEY, DATABASE_URL, POKEAPI_BASE_URL, POKEAPI_CACHE_DIR).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/pokemon-chatbot-api/internal/config"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+const usage = `usage: pokectl [-o table|json] <command> [args]
+
+commands:
+  users list [-q text] [-sort column] [-order asc|desc] [-page n] [-limit n]
+  users find <telegram_id>
+  users delete [-yes] <telegram_id>
+  stats [-since 7d] [-until 2025-11-30]
+  searches purge -before 90d [-yes]
+  export users|searches [-format csv|json] [-out file]
+  cache warm [-from 1 -to 151] [name ...]
+  pokedex import -dump <api/v2 dir> [-out pokedex.json.gz]
+  pokedex info <file>
+  migrate up | down [steps] | status
+`
+
+// app holds what every command needs; services are built on first use so
+// commands that don't touch Supabase work without credentials
+type app struct {
+	cfg *config.Config
+	out *printer
+}
+
+func main() {
+	godotenv.Load()
+
+	global := flag.NewFlagSet("pokectl", flag.ExitOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	output := global.String("o", "table", "output format: table or json")
+	global.Parse(os.Args[1:])
+
+	if *output != "table" && *output != "json" {
+		fail(fmt.Errorf("-o must be table or json"))
+	}
+	args := global.Args()
+	if len(args) == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	a := &app{
+		cfg: config.New(),
+		out: &printer{w: os.Stdout, json: *output == "json"},
+	}
+
+	var err error
+	switch args[0] {
+	case "users":
+		err = a.users(args[1:])
+	case "stats":
+		err = a.stats(args[1:])
+	case "searches":
+		err = a.searches(args[1:])
+	case "export":
+		err = a.export(args[1:])
+	case "cache":
+		err = a.cache(args[1:])
+	case "pokedex":
+		err = a.pokedex(args[1:])
+	case "migrate":
+		err = a.migrate(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "pokectl:", err)
+	os.Exit(1)
+}
+
+func (a *app) requireSupabase() error {
+	if a.cfg.SupabaseURL == "" || a.cfg.SupabaseKey == "" {
+		return fmt.Errorf("SUPABASE_URL and SUPABASE_KEY are required")
+	}
+	return nil
+}
+
+func (a *app) userService() (services.UserService, error) {
+	if err := a.requireSupabase(); err != nil {
+		return nil, err
+	}
+	return services.NewUserService(repository.NewUserRepository(a.cfg.SupabaseURL, a.cfg.SupabaseKey)), nil
+}
+
+func (a *app) searchRepo() (repository.SearchRepository, error) {
+	if err := a.requireSupabase(); err != nil {
+		return nil, err
+	}
+	return repository.NewSearchRepository(a.cfg.SupabaseURL, a.cfg.SupabaseKey), nil
+}
+
+// confirm asks for a y/N answer on stdin unless yes is already set
+func confirm(yes bool, prompt string) bool {
+	if yes {
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// parseTimeArg accepts a relative age (90m, 24h, 7d, 2w) meaning that long
+// ago, a date (2006-01-02) or an RFC3339 timestamp
+func parseTimeArg(value string) (time.Time, error) {
+	if n := len(value); n > 1 {
+		unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[value[n-1]]
+		if unit > 0 {
+			if count, err := strconv.Atoi(value[:n-1]); err == nil {
+				return time.Now().Add(-time.Duration(count) * unit), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected e.g. 7d, 24h, 2025-11-01 or RFC3339", value)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/migrations"
+)
+
+func (a *app) migrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: pokectl migrate up | down [steps] | status")
+	}
+	if a.cfg.DatabaseURL == "" {
+		return fmt.Errorf("DATABASE_URL is required to run migrations")
+	}
+
+	db, dialect, err := migrations.Open(a.cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		if printErr := a.printMigrations("applied", applied); printErr != nil {
+			return printErr
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number")
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		if printErr := a.printMigrations("reverted", reverted); printErr != nil {
+			return printErr
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(statuses))
+		for i, s := range statuses {
+			state, appliedAt := "pending", ""
+			if s.Applied {
+				state = "applied"
+				appliedAt = formatTime(s.AppliedAt)
+			}
+			rows[i] = []string{fmt.Sprintf("%04d", s.Version), s.Name, state, appliedAt}
+		}
+		return a.out.print(statuses, []string{"VERSION", "NAME", "STATUS", "APPLIED_AT"}, rows)
+
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
+
+func (a *app) printMigrations(action string, done []migrations.Migration) error {
+	names := make([]string, len(done))
+	rows := make([][]string, len(done))
+	for i, m := range done {
+		names[i] = fmt.Sprintf("%04d_%s", m.Version, m.Name)
+		rows[i] = []string{fmt.Sprintf("%04d", m.Version), m.Name}
+	}
+	if a.out.json {
+		return a.out.printJSON(map[string]interface{}{action: names})
+	}
+	if len(done) == 0 {
+		fmt.Println("nothing to do")
+		return nil
+	}
+	return a.out.print(nil, []string{"VERSION", strings.ToUpper(action)}, rows)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer renders command results as an aligned table or as JSON
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// print writes v as JSON, or as a table of headers/rows in table mode
+func (p *printer) print(v interface{}, headers []string, rows [][]string) error {
+	if p.json {
+		return p.printJSON(v)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// message writes a one-line status, wrapped in an object in JSON mode
+func (p *printer) message(key string, value interface{}, text string) error {
+	if p.json {
+		return p.printJSON(map[string]interface{}{key: value})
+	}
+	_, err := fmt.Fprintln(p.w, text)
+	return err
+}
+
+func (p *printer) printJSON(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokedex"
+)
+
+func (a *app) pokedex(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: pokectl pokedex import -dump <api/v2 dir> -out <file> | info <file>")
+	}
+
+	switch args[0] {
+	case "import":
+		fs := flag.NewFlagSet("pokedex import", flag.ExitOnError)
+		dump := fs.String("dump", "", "PokeAPI dump directory (api-data's data/api/v2)")
+		out := fs.String("out", "pokedex.json.gz", "snapshot file to write")
+		fs.Parse(args[1:])
+
+		if *dump == "" {
+			return fmt.Errorf("-dump is required")
+		}
+		store, err := pokedex.Import(*dump, func(kind string, count int) {
+			if !a.out.json {
+				fmt.Fprintf(os.Stderr, "imported %d %s\n", count, kind)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if err := store.Save(*out); err != nil {
+			return err
+		}
+		return a.printPokedex(store, *out)
+
+	case "info":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: pokectl pokedex info <file>")
+		}
+		store, err := pokedex.Load(args[1])
+		if err != nil {
+			return err
+		}
+		return a.printPokedex(store, args[1])
+
+	default:
+		return fmt.Errorf("unknown pokedex command %q", args[0])
+	}
+}
+
+func (a *app) printPokedex(store *pokedex.Store, path string) error {
+	counts := make(map[string]int)
+	rows := make([][]string, len(pokedex.Kinds))
+	for i, kind := range pokedex.Kinds {
+		counts[kind] = store.Count(kind)
+		rows[i] = []string{kind, strconv.Itoa(counts[kind])}
+	}
+
+	if a.out.json {
+		return a.out.printJSON(map[string]interface{}{
+			"path":     path,
+			"built_at": store.BuiltAt,
+			"counts":   counts,
+		})
+	}
+	fmt.Printf("%s (built %s)\n\n", path, store.BuiltAt.Format("2006-01-02 15:04:05"))
+	return a.out.print(nil, []string{"RESOURCE", "ENTRIES"}, rows)
+}
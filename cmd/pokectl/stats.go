@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+func (a *app) stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	since := fs.String("since", "", "only count searches after this time (7d, 24h, 2025-11-01, RFC3339)")
+	until := fs.String("until", "", "only count searches before this time")
+	fs.Parse(args)
+
+	var window repository.TimeWindow
+	if *since != "" {
+		t, err := parseTimeArg(*since)
+		if err != nil {
+			return err
+		}
+		window.From = &t
+	}
+	if *until != "" {
+		t, err := parseTimeArg(*until)
+		if err != nil {
+			return err
+		}
+		window.To = &t
+	}
+
+	repo, err := a.searchRepo()
+	if err != nil {
+		return err
+	}
+	stats, err := repo.GetStats(window)
+	if err != nil {
+		return err
+	}
+
+	if a.out.json {
+		return a.out.printJSON(stats)
+	}
+
+	fmt.Printf("total searches:     %d\n", stats.TotalSearches)
+	fmt.Printf("found searches:     %d\n", stats.FoundSearches)
+	fmt.Printf("not found searches: %d\n\n", stats.NotFoundSearches)
+
+	rows := make([][]string, len(stats.TopSearched))
+	for i, item := range stats.TopSearched {
+		rows[i] = []string{strconv.Itoa(i + 1), item.PokemonName, strconv.Itoa(item.Count)}
+	}
+	return a.out.print(nil, []string{"RANK", "POKEMON", "SEARCHES"}, rows)
+}
+
+func (a *app) searches(args []string) error {
+	if len(args) == 0 || args[0] != "purge" {
+		return fmt.Errorf("usage: pokectl searches purge -before 90d [-yes]")
+	}
+
+	fs := flag.NewFlagSet("searches purge", flag.ExitOnError)
+	before := fs.String("before", "", "delete searches older than this (90d, 2025-11-01, RFC3339)")
+	yes := fs.Bool("yes", false, "skip confirmation")
+	fs.Parse(args[1:])
+
+	if *before == "" {
+		return fmt.Errorf("-before is required")
+	}
+	cutoff, err := parseTimeArg(*before)
+	if err != nil {
+		return err
+	}
+
+	repo, err := a.searchRepo()
+	if err != nil {
+		return err
+	}
+	if !confirm(*yes, fmt.Sprintf("Delete searches before %s?", cutoff.Format("2006-01-02 15:04:05"))) {
+		return fmt.Errorf("aborted")
+	}
+
+	deleted, err := repo.DeleteBefore(cutoff)
+	if err != nil {
+		return err
+	}
+	return a.out.message("deleted", deleted, fmt.Sprintf("deleted %d searches", deleted))
+}
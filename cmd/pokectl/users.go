@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/models"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+func (a *app) users(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: pokectl users list|find|delete")
+	}
+
+	service, err := a.userService()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("users list", flag.ExitOnError)
+		query := fs.String("q", "", "substring of first name, last name or username")
+		sort := fs.String("sort", repository.SortRegisteredAt, "registered_at, last_active or first_name")
+		order := fs.String("order", "desc", "asc or desc")
+		page := fs.Int("page", 1, "page number")
+		limit := fs.Int("limit", 20, "users per page")
+		fs.Parse(args[1:])
+
+		if !repository.IsValidUserSort(*sort) {
+			return fmt.Errorf("-sort must be one of registered_at, last_active, first_name")
+		}
+		if *page < 1 || *limit < 1 {
+			return fmt.Errorf("-page and -limit must be positive")
+		}
+
+		opts := repository.UserListOptions{Query: *query, SortBy: *sort, SortAsc: *order == "asc"}
+		users, total, err := service.GetUsersPaginated(opts, *page, *limit)
+		if err != nil {
+			return err
+		}
+
+		if err := a.out.print(map[string]interface{}{
+			"users": users,
+			"total": total,
+			"page":  *page,
+			"limit": *limit,
+		}, userHeaders, userRows(users)); err != nil {
+			return err
+		}
+		if !a.out.json {
+			fmt.Printf("\npage %d of %d (%d users)\n", *page, (total+*limit-1) / *limit, total)
+		}
+		return nil
+
+	case "find":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: pokectl users find <telegram_id>")
+		}
+		user, err := service.GetUserByTelegramID(args[1])
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return fmt.Errorf("user %s not found", args[1])
+		}
+		if err != nil {
+			return err
+		}
+		return a.out.print(user, userHeaders, userRows([]models.User{*user}))
+
+	case "delete":
+		fs := flag.NewFlagSet("users delete", flag.ExitOnError)
+		yes := fs.Bool("yes", false, "skip confirmation")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: pokectl users delete [-yes] <telegram_id>")
+		}
+		telegramID := fs.Arg(0)
+
+		if !confirm(*yes, fmt.Sprintf("Delete user %s?", telegramID)) {
+			return fmt.Errorf("aborted")
+		}
+		if err := service.DeleteUser(telegramID); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return fmt.Errorf("user %s not found", telegramID)
+			}
+			return err
+		}
+		return a.out.message("deleted", telegramID, "deleted user "+telegramID)
+
+	default:
+		return fmt.Errorf("unknown users command %q", args[0])
+	}
+}
+
+var userHeaders = []string{"ID", "TELEGRAM_ID", "USERNAME", "FIRST_NAME", "LAST_NAME", "REGISTERED_AT", "LAST_ACTIVE"}
+
+func userRows(users []models.User) [][]string {
+	rows := make([][]string, len(users))
+	for i, u := range users {
+		rows[i] = []string{
+			strconv.Itoa(u.ID),
+			u.TelegramID,
+			u.Username,
+			u.FirstName,
+			u.LastName,
+			formatTime(u.RegisteredAt),
+			formatTime(u.LastActive),
+		}
+	}
+	return rows
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
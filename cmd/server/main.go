@@ -3,14 +3,19 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/yourusername/pokemon-chatbot-api/internal/config"
 	"github.com/yourusername/pokemon-chatbot-api/internal/handlers"
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokedex"
 	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
 	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/session"
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegram"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
 )
 
 func main() {
@@ -22,17 +27,97 @@ func main() {
 	// Initialize config
 	cfg := config.New()
 
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(cfg.SupabaseURL, cfg.SupabaseKey)
 	searchRepo := repository.NewSearchRepository(cfg.SupabaseURL, cfg.SupabaseKey)
+	favoriteRepo := repository.NewFavoriteRepository(cfg.SupabaseURL, cfg.SupabaseKey)
+	teamRepo := repository.NewTeamRepository(cfg.SupabaseURL, cfg.SupabaseKey)
+
+	// Initialize PokeAPI cache
+	pokeCache := services.NewMemoryCache(24*time.Hour, 2000)
+	if cfg.PokeAPICacheDir != "" {
+		fileCache, err := services.NewFileCache(cfg.PokeAPICacheDir, 0)
+		if err != nil {
+			log.Fatal("Failed to create PokeAPI cache:", err)
+		}
+		pokeCache = fileCache
+	}
+
+	pokemonOpts := []services.PokemonOption{
+		services.WithCache(pokeCache),
+		services.WithBaseURL(cfg.PokeAPIBaseURL),
+		services.WithFavorites(favoriteRepo),
+	}
+	if cfg.PokedexPath != "" {
+		store, err := pokedex.Load(cfg.PokedexPath)
+		if err != nil {
+			log.Fatal("Failed to load Pokedex snapshot:", err)
+		}
+		log.Printf("Loaded Pokedex snapshot with %d Pokemon (offline: %t)", store.Count(pokedex.Pokemon), cfg.PokeAPIOffline)
+		pokemonOpts = append(pokemonOpts, services.WithPokedex(store, cfg.PokeAPIOffline))
+	} else if cfg.PokeAPIOffline {
+		log.Fatal("POKEAPI_OFFLINE requires POKEDEX_PATH")
+	}
+
+	// Reply templates, with overrides from MESSAGE_TEMPLATES_DIR
+	messageTemplates, err := templates.Load(cfg.MessageTemplatesDir)
+	if err != nil {
+		log.Fatal("Failed to load message templates:", err)
+	}
+	pokemonOpts = append(pokemonOpts, services.WithTemplates(messageTemplates))
 
 	// Initialize services
 	userService := services.NewUserService(userRepo)
-	pokemonService := services.NewPokemonService(searchRepo)
+	pokemonService := services.NewPokemonService(searchRepo, pokemonOpts...)
+
+	// Localized names resolve once their species are known; learn them all
+	// without holding up startup
+	go func() {
+		n, err := pokemonService.LoadNameAliases()
+		if err != nil {
+			log.Printf("Loaded localized names of %d species, some failed: %v", n, err)
+			return
+		}
+		log.Printf("Loaded localized names of %d species", n)
+	}()
+
+	sessionTTL := 30 * time.Minute
+	if cfg.SessionTTL != "" {
+		ttl, err := time.ParseDuration(cfg.SessionTTL)
+		if err != nil || ttl <= 0 {
+			log.Fatal("Invalid SESSION_TTL: ", cfg.SessionTTL)
+		}
+		sessionTTL = ttl
+	}
+	sessionService := services.NewSessionService(session.NewMemoryStore(sessionTTL), userService, pokemonService)
+
+	favoritesLimit := services.DefaultFavoritesLimit
+	if cfg.FavoritesLimit != "" {
+		limit, err := strconv.Atoi(cfg.FavoritesLimit)
+		if err != nil || limit <= 0 {
+			log.Fatal("Invalid FAVORITES_LIMIT: ", cfg.FavoritesLimit)
+		}
+		favoritesLimit = limit
+	}
+	favoriteService := services.NewFavoriteService(favoriteRepo, userService, pokemonService, favoritesLimit)
+	teamService := services.NewTeamService(teamRepo, userService, pokemonService)
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService)
 	pokemonHandler := handlers.NewPokemonHandler(pokemonService)
+	adminHandler := handlers.NewAdminHandler(pokemonService, messageTemplates)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	nluHandler := handlers.NewNLUHandler(pokemonService)
+	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+	teamHandler := handlers.NewTeamHandler(teamService)
 
 	// Setup router
 	router := gin.Default()
@@ -60,20 +145,80 @@ func main() {
 			users.POST("/register", userHandler.Register)
 			users.GET("/:telegramId", userHandler.GetUser)
 			users.GET("/:telegramId/check", userHandler.CheckRegistration)
+			users.PUT("/:telegramId/language", userHandler.SetLanguage)
+			users.GET("/:telegramId/favorites", handlers.Language(userService), favoriteHandler.ListFavorites)
+			users.POST("/:telegramId/favorites/:pokemon", favoriteHandler.AddFavorite)
+			users.DELETE("/:telegramId/favorites/:pokemon", favoriteHandler.RemoveFavorite)
+			users.GET("/:telegramId/teams", teamHandler.ListTeams)
+			users.POST("/:telegramId/teams", teamHandler.CreateTeam)
+			users.GET("/:telegramId/teams/:teamId", teamHandler.GetTeam)
+			users.PUT("/:telegramId/teams/:teamId", teamHandler.UpdateTeam)
+			users.DELETE("/:telegramId/teams/:teamId", teamHandler.DeleteTeam)
+			users.GET("/:telegramId/teams/:teamId/analysis", teamHandler.AnalyzeTeam)
 		}
 
 		// Pokemon routes
-		pokemon := api.Group("/pokemon")
+		pokemon := api.Group("/pokemon", handlers.Language(userService))
 		{
+			pokemon.GET("/compare", pokemonHandler.ComparePokemon)
 			pokemon.GET("/:name", pokemonHandler.GetPokemon)
+			pokemon.GET("/:name/evolution", pokemonHandler.GetEvolution)
+			pokemon.GET("/:name/matchups", pokemonHandler.GetMatchups)
+			pokemon.GET("/:name/moves", pokemonHandler.GetMoves)
 			pokemon.GET("/search/:query", pokemonHandler.SearchPokemon)
 		}
 
+		// Chat session routes
+		sessions := api.Group("/sessions", handlers.Language(userService))
+		{
+			sessions.GET("/:telegramId", sessionHandler.GetSession)
+			sessions.PUT("/:telegramId", sessionHandler.UpdateSession)
+			sessions.DELETE("/:telegramId", sessionHandler.EndSession)
+		}
+
+		// Intent detection
+		nlu := api.Group("/nlu", handlers.Language(userService))
+		{
+			nlu.POST("/parse", nluHandler.Parse)
+		}
+
+		// Type chart, move and ability routes
+		api.GET("/types/:type", pokemonHandler.GetType)
+		api.GET("/moves/:move", pokemonHandler.GetMove)
+		api.GET("/abilities/:ability", pokemonHandler.GetAbility)
+
 		// Stats routes
 		stats := api.Group("/stats")
 		{
 			stats.GET("/searches", pokemonHandler.GetSearchStats)
 		}
+
+		// Search log routes
+		api.GET("/searches", pokemonHandler.ListSearches)
+
+		// Admin routes
+		admin := api.Group("/admin", handlers.AdminAuth(cfg.AdminToken, cfg.AdminOpen))
+		{
+			admin.GET("/templates", adminHandler.ListTemplates)
+			admin.POST("/templates/preview", adminHandler.PreviewTemplate)
+		}
+	}
+
+	// v2 routes return structured fields; /api keeps the v1 shape the
+	// Kata bot flow relies on
+	v2 := router.Group("/api/v2", handlers.Language(userService))
+	{
+		v2.GET("/pokemon/:name", pokemonHandler.GetPokemonV2)
+	}
+
+	// Telegram webhook, for running the bot without Kata
+	if cfg.TelegramBotToken != "" && cfg.TelegramWebhookSecret != "" {
+		bot := telegram.NewBot(telegram.NewClient(cfg.TelegramAPIURL, cfg.TelegramBotToken), userService, pokemonService)
+		telegramHandler := handlers.NewTelegramHandler(bot, cfg.TelegramWebhookSecret)
+		router.POST("/telegram/webhook/:secret", telegramHandler.Webhook)
+		log.Println("Telegram webhook enabled")
+	} else if cfg.TelegramBotToken != "" {
+		log.Println("TELEGRAM_BOT_TOKEN is set without TELEGRAM_WEBHOOK_SECRET; Telegram webhook disabled")
 	}
 
 	// Start server
@@ -92,7 +237,7 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Telegram-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
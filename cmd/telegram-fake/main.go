@@ -0,0 +1,39 @@
+// Command telegram-fake stands in for the Telegram Bot API on a local port
+// and logs every message and inline answer the bot sends:
+//
+//	go run ./cmd/telegram-fake -addr :8082 -token test-token
+//	TELEGRAM_API_URL=http://localhost:8082 TELEGRAM_BOT_TOKEN=test-token go run ./cmd/server
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegramfake"
+)
+
+func main() {
+	addr := flag.String("addr", ":8082", "address to listen on")
+	token := flag.String("token", "test-token", "bot token to accept")
+	flag.Parse()
+
+	server := telegramfake.New(*token)
+	server.OnSend = func(msg telegramfake.Sent) {
+		switch msg.Method {
+		case "sendPhoto":
+			log.Printf("chat %d <- photo %s [%s] %q", msg.ChatID, msg.Photo, msg.ParseMode, msg.Caption)
+		case "answerInlineQuery":
+			titles := make([]string, len(msg.Results))
+			for i, r := range msg.Results {
+				titles[i] = r.Title
+			}
+			log.Printf("inline query %s <- %d results %v (next_offset %q)", msg.InlineQueryID, len(msg.Results), titles, msg.NextOffset)
+		default:
+			log.Printf("chat %d <- [%s] %q", msg.ChatID, msg.ParseMode, msg.Text)
+		}
+	}
+
+	log.Printf("Fake Telegram Bot API listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
@@ -8,12 +8,57 @@ type Config struct {
 	SupabaseURL string
 	SupabaseKey string
 	Port        string
+	DatabaseURL string
+	// PokeAPIBaseURL overrides the PokeAPI v2 endpoint, e.g. to use
+	// cmd/pokeapi-fake during development
+	PokeAPIBaseURL string
+	// PokeAPICacheDir persists PokeAPI responses on disk when set;
+	// otherwise they are cached in memory
+	PokeAPICacheDir string
+	// PokedexPath points at a snapshot built by `pokectl pokedex import`
+	PokedexPath string
+	// PokeAPIOffline never calls PokeAPI, serving only from the snapshot
+	PokeAPIOffline bool
+	// MessageTemplatesDir holds <channel>.tmpl files replacing the built-in
+	// reply templates
+	MessageTemplatesDir string
+	// AdminToken is the bearer token of the /api/admin routes
+	AdminToken string
+	// AdminOpen leaves the admin routes unauthenticated when no token is
+	// set, for local development only
+	AdminOpen bool
+	// TelegramBotToken and TelegramWebhookSecret enable the
+	// /telegram/webhook/:secret route when both are set
+	TelegramBotToken      string
+	TelegramWebhookSecret string
+	// TelegramAPIURL overrides the Bot API endpoint, e.g. to use
+	// cmd/telegram-fake during development
+	TelegramAPIURL string
+	// SessionTTL is how long a chat session lives without updates, as a
+	// Go duration such as "30m"
+	SessionTTL string
+	// FavoritesLimit is how many favorites a user may keep
+	FavoritesLimit string
 }
 
 func New() *Config {
 	return &Config{
-		SupabaseURL: os.Getenv("SUPABASE_URL"),
-		SupabaseKey: os.Getenv("SUPABASE_KEY"),
-		Port:        os.Getenv("PORT"),
+		SupabaseURL:         os.Getenv("SUPABASE_URL"),
+		SupabaseKey:         os.Getenv("SUPABASE_KEY"),
+		Port:                os.Getenv("PORT"),
+		DatabaseURL:         os.Getenv("DATABASE_URL"),
+		PokeAPIBaseURL:      os.Getenv("POKEAPI_BASE_URL"),
+		PokeAPICacheDir:     os.Getenv("POKEAPI_CACHE_DIR"),
+		PokedexPath:         os.Getenv("POKEDEX_PATH"),
+		PokeAPIOffline:      os.Getenv("POKEAPI_OFFLINE") == "true",
+		MessageTemplatesDir: os.Getenv("MESSAGE_TEMPLATES_DIR"),
+		AdminToken:          os.Getenv("ADMIN_TOKEN"),
+		AdminOpen:           os.Getenv("ADMIN_OPEN") == "true",
+
+		TelegramBotToken:      os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramWebhookSecret: os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+		TelegramAPIURL:        os.Getenv("TELEGRAM_API_URL"),
+		SessionTTL:            os.Getenv("SESSION_TTL"),
+		FavoritesLimit:        os.Getenv("FAVORITES_LIMIT"),
 	}
 }
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+)
+
+type AdminHandler struct {
+	service   services.PokemonService
+	templates *templates.Set
+}
+
+func NewAdminHandler(service services.PokemonService, set *templates.Set) *AdminHandler {
+	return &AdminHandler{service: service, templates: set}
+}
+
+// AdminAuth requires "Authorization: Bearer <token>" on admin routes. With
+// no token configured the routes answer 503, unless open is set for local
+// development.
+func AdminAuth(token string, open bool) gin.HandlerFunc {
+	if token == "" && open {
+		log.Println("ADMIN_OPEN is set without ADMIN_TOKEN; admin routes are unauthenticated")
+		return func(c *gin.Context) { c.Next() }
+	}
+	if token == "" {
+		log.Println("ADMIN_TOKEN is not set; admin routes are disabled")
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"success": false,
+				"error":   "Admin routes are disabled",
+			})
+		}
+	}
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Invalid admin token",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// TemplateSource is a channel's message template
+type TemplateSource struct {
+	Channel  string `json:"channel"`
+	Template string `json:"template"`
+}
+
+// ListTemplates serves the template of every channel
+func (h *AdminHandler) ListTemplates(c *gin.Context) {
+	sources := make([]TemplateSource, 0, len(templates.Channels))
+	for _, channel := range templates.Channels {
+		source, _ := h.templates.Source(channel)
+		sources = append(sources, TemplateSource{Channel: channel, Template: source})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sources,
+	})
+}
+
+type PreviewRequest struct {
+	Channel string `json:"channel" binding:"required"`
+	Pokemon string `json:"pokemon" binding:"required"`
+	Lang    string `json:"lang"`
+	// Template replaces the channel's template for the preview
+	Template string `json:"template"`
+}
+
+// PreviewTemplate renders the message a channel would send for a Pokemon,
+// with the configured template or one given in the request
+func (h *AdminHandler) PreviewTemplate(c *gin.Context) {
+	var req PreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "channel and pokemon are required",
+		})
+		return
+	}
+	if !templates.IsChannel(req.Channel) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "channel must be one of " + strings.Join(templates.Channels, ", "),
+		})
+		return
+	}
+	lang := i18n.Default
+	if req.Lang != "" {
+		normalized, ok := i18n.Normalize(req.Lang)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "lang must be one of " + strings.Join(i18n.Supported(), ", "),
+			})
+			return
+		}
+		lang = normalized
+	}
+
+	opts := services.MessageOptions{Lang: lang, Channel: req.Channel}
+	result, err := h.service.PreviewMessage(req.Pokemon, opts, req.Template)
+	if errors.Is(err, templates.ErrInvalidTemplate) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to preview template",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"channel": req.Channel,
+			"lang":    lang,
+			"found":   result.Found,
+			"message": result.Message,
+		},
+	})
+}
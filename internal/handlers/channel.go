@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+)
+
+// messageOptions reads the message language and the ?channel= the reply is
+// formatted for. An unknown channel is answered with a 400 and ok is false.
+func messageOptions(c *gin.Context) (opts services.MessageOptions, ok bool) {
+	opts = services.MessageOptions{Lang: languageOf(c), Channel: c.Query("channel")}
+	if opts.Channel != "" && !templates.IsChannel(opts.Channel) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"found": false,
+			"error": "channel must be one of " + strings.Join(templates.Channels, ", "),
+		})
+		return opts, false
+	}
+	return opts, true
+}
+
+// kataFormat reports whether ?format=kata asked for Kata messages instead
+// of JSON. Any other format but json is answered with a 400 and ok is false.
+func kataFormat(c *gin.Context) (asKata, ok bool) {
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		return false, true
+	case "kata":
+		return true, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"found": false,
+		"error": "format must be json or kata",
+	})
+	return false, false
+}
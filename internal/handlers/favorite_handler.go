@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+type FavoriteHandler struct {
+	service services.FavoriteService
+}
+
+func NewFavoriteHandler(service services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{service: service}
+}
+
+// ListFavorites serves a user's favorites with the data of each Pokemon
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	result, err := h.service.ListFavorites(c.Param("telegramId"), languageOf(c))
+	if err != nil {
+		favoriteError(c, err, "Failed to get favorites")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// AddFavorite saves a Pokemon as a favorite; adding one twice succeeds with
+// exists set
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	result, err := h.service.AddFavorite(c.Param("telegramId"), c.Param("pokemon"))
+	if err != nil {
+		favoriteError(c, err, "Failed to add favorite")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	if err := h.service.RemoveFavorite(c.Param("telegramId"), c.Param("pokemon")); err != nil {
+		favoriteError(c, err, "Failed to remove favorite")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+func favoriteError(c *gin.Context, err error, fallback string) {
+	status, message := http.StatusInternalServerError, fallback
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		status, message = http.StatusNotFound, "User not found"
+	case errors.Is(err, services.ErrPokemonNotFound):
+		status, message = http.StatusNotFound, "Pokemon not found"
+	case errors.Is(err, repository.ErrFavoriteNotFound):
+		status, message = http.StatusNotFound, "Favorite not found"
+	case errors.Is(err, services.ErrFavoritesFull):
+		status, message = http.StatusConflict, err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   message,
+	})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+// languageKey is the gin context key holding the request language
+const languageKey = "lang"
+
+// Language picks the language a request is answered in, from the first of:
+// ?lang=, the stored preference of the user named by ?telegram_id= (or the
+// X-Telegram-ID header, or a :telegramId route param), and the
+// Accept-Language header. An unsupported ?lang= is a 400. The choice is
+// echoed in Content-Language.
+func Language(users services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := ""
+		if value := c.Query("lang"); value != "" {
+			normalized, ok := i18n.Normalize(value)
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"found": false,
+					"error": "lang must be one of " + strings.Join(i18n.Supported(), ", "),
+				})
+				return
+			}
+			lang = normalized
+		}
+
+		if lang == "" && users != nil {
+			telegramID := c.Query("telegram_id")
+			if telegramID == "" {
+				telegramID = c.GetHeader("X-Telegram-ID")
+			}
+			if telegramID == "" {
+				telegramID = c.Param("telegramId")
+			}
+			if telegramID != "" {
+				// An unknown user or a failed lookup just falls through
+				if user, err := users.GetUserByTelegramID(telegramID); err == nil {
+					lang, _ = i18n.Normalize(user.Language)
+				}
+			}
+		}
+
+		if lang == "" {
+			lang = i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
+		}
+		if lang == "" {
+			lang = i18n.Default
+		}
+
+		c.Set(languageKey, lang)
+		c.Header("Content-Language", lang)
+		c.Next()
+	}
+}
+
+// languageOf returns the language chosen by the Language middleware, the
+// default when it didn't run
+func languageOf(c *gin.Context) string {
+	if lang := c.GetString(languageKey); lang != "" {
+		return lang
+	}
+	return i18n.Default
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+// maxParseLength is the longest message, in characters, the NLU reads
+const maxParseLength = 500
+
+type NLUHandler struct {
+	service services.PokemonService
+}
+
+func NewNLUHandler(service services.PokemonService) *NLUHandler {
+	return &NLUHandler{service: service}
+}
+
+type ParseRequest struct {
+	Text string `json:"text" binding:"required"`
+	// Lang overrides the request's language
+	Lang string `json:"lang"`
+}
+
+// Parse classifies a chat message into an intent with its entities
+func (h *NLUHandler) Parse(c *gin.Context) {
+	var req ParseRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "text is required",
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.Text) > maxParseLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "text must be at most 500 characters",
+		})
+		return
+	}
+	lang := languageOf(c)
+	if req.Lang != "" {
+		normalized, ok := i18n.Normalize(req.Lang)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "lang must be one of " + strings.Join(i18n.Supported(), ", "),
+			})
+			return
+		}
+		lang = normalized
+	}
+
+	result, err := h.service.ParseMessage(req.Text, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to parse message",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// parseLimit reads the limit query param, clamped to 1..maxPageLimit
+func parseLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return limit
+}
+
+// pageURL returns the current request URL with the given query params set
+// and others removed, keeping filters so follow-up pages stay consistent
+func pageURL(c *gin.Context, set map[string]string, remove ...string) string {
+	query := c.Request.URL.Query()
+	for _, key := range remove {
+		query.Del(key)
+	}
+	for key, value := range set {
+		query.Set(key, value)
+	}
+	return c.Request.URL.Path + "?" + query.Encode()
+}
+
+// setLinkHeader writes an RFC 8288 Link header from rel -> URL pairs
+func setLinkHeader(c *gin.Context, links map[string]string) {
+	var parts []string
+	for _, rel := range []string{"first", "prev", "next", "last"} {
+		if link, ok := links[rel]; ok {
+			parts = append(parts, fmt.Sprintf(`<%s>; rel="%s"`, link, rel))
+		}
+	}
+	if len(parts) > 0 {
+		c.Header("Link", strings.Join(parts, ", "))
+	}
+}
@@ -1,10 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/kata"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
 	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
 )
 
 type PokemonHandler struct {
@@ -26,7 +35,42 @@ func (h *PokemonHandler) GetPokemon(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.GetPokemon(name)
+	asKata, ok := kataFormat(c)
+	if !ok {
+		return
+	}
+	opts, ok := messageOptions(c)
+	if !ok {
+		return
+	}
+	if asKata && opts.Channel == "" {
+		opts.Channel = templates.Kata
+	}
+
+	result, err := h.service.GetPokemon(name, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch Pokemon data",
+		})
+		return
+	}
+
+	if asKata {
+		c.JSON(http.StatusOK, kata.Pokemon(result, opts.Lang))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPokemonV2 serves the structured v2 Pokemon shape
+func (h *PokemonHandler) GetPokemonV2(c *gin.Context) {
+	opts, ok := messageOptions(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetPokemonV2(c.Param("name"), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"found": false,
@@ -38,6 +82,203 @@ func (h *PokemonHandler) GetPokemon(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetEvolution serves the evolution tree of a Pokemon's family
+func (h *PokemonHandler) GetEvolution(c *gin.Context) {
+	name := c.Param("name")
+
+	result, err := h.service.GetEvolution(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch evolution data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMatchups serves the type weaknesses, resistances and immunities of a
+// Pokemon, using an older generation's chart when ?generation= is set
+func (h *PokemonHandler) GetMatchups(c *gin.Context) {
+	generation, ok := parseGeneration(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetMatchups(c.Param("name"), generation)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch matchup data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetType serves how a type fares attacking and defending
+func (h *PokemonHandler) GetType(c *gin.Context) {
+	generation, ok := parseGeneration(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetType(c.Param("type"), generation)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch type data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parseGeneration reads the optional generation query param, answering 400
+// when it is invalid. Zero means the current generation.
+func parseGeneration(c *gin.Context) (int, bool) {
+	value := c.Query("generation")
+	if value == "" {
+		return 0, true
+	}
+	generation, err := strconv.Atoi(value)
+	if err != nil || generation < 1 || generation > typechart.LatestGeneration {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"found": false,
+			"error": fmt.Sprintf("generation must be between 1 and %d", typechart.LatestGeneration),
+		})
+		return 0, false
+	}
+	return generation, true
+}
+
+// ComparePokemon serves a side-by-side comparison of the Pokemon listed in
+// ?names=pikachu,raichu
+func (h *PokemonHandler) ComparePokemon(c *gin.Context) {
+	asKata, ok := kataFormat(c)
+	if !ok {
+		return
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, value := range c.QueryArray("names") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+
+	if len(names) < services.MinCompare || len(names) > services.MaxCompare {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"found": false,
+			"error": fmt.Sprintf("names must list between %d and %d different Pokemon", services.MinCompare, services.MaxCompare),
+		})
+		return
+	}
+
+	result, err := h.service.ComparePokemon(names)
+	if errors.Is(err, services.ErrTooFewToCompare) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"found": false,
+			"error": fmt.Sprintf("names must list between %d and %d different Pokemon", services.MinCompare, services.MaxCompare),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to compare Pokemon",
+		})
+		return
+	}
+
+	if asKata {
+		c.JSON(http.StatusOK, kata.Compare(result, names, languageOf(c)))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMoves serves the moves a Pokemon learns, filtered by ?version_group=
+// and ?method= and sorted by ?sort=level|name
+func (h *PokemonHandler) GetMoves(c *gin.Context) {
+	filter := services.MoveFilter{
+		VersionGroup: c.Query("version_group"),
+		Method:       strings.ToLower(c.Query("method")),
+		Sort:         strings.ToLower(c.DefaultQuery("sort", services.MoveSortLevel)),
+	}
+	if filter.Method != "" && !contains(services.LearnMethods, filter.Method) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"found": false,
+			"error": "method must be one of " + strings.Join(services.LearnMethods, ", "),
+		})
+		return
+	}
+	if filter.Sort != services.MoveSortLevel && filter.Sort != services.MoveSortName {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"found": false,
+			"error": "sort must be level or name",
+		})
+		return
+	}
+
+	result, err := h.service.GetMoves(c.Param("name"), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch move data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMove serves the details of one move
+func (h *PokemonHandler) GetMove(c *gin.Context) {
+	result, err := h.service.GetMove(c.Param("move"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch move data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetAbility serves an ability's effect and the Pokemon that have it
+func (h *PokemonHandler) GetAbility(c *gin.Context) {
+	result, err := h.service.GetAbility(c.Param("ability"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"found": false,
+			"error": "Failed to fetch ability data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *PokemonHandler) SearchPokemon(c *gin.Context) {
 	query := c.Param("query")
 
@@ -49,9 +290,21 @@ func (h *PokemonHandler) SearchPokemon(c *gin.Context) {
 		return
 	}
 
+	asKata, ok := kataFormat(c)
+	if !ok {
+		return
+	}
+	opts, ok := messageOptions(c)
+	if !ok {
+		return
+	}
+	if asKata && opts.Channel == "" {
+		opts.Channel = templates.Kata
+	}
+
 	// For now, search is same as get
 	// You can enhance this later with fuzzy search
-	result, err := h.service.GetPokemon(query)
+	result, err := h.service.GetPokemon(query, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"found": false,
@@ -60,11 +313,41 @@ func (h *PokemonHandler) SearchPokemon(c *gin.Context) {
 		return
 	}
 
+	if asKata {
+		c.JSON(http.StatusOK, kata.Pokemon(result, opts.Lang))
+		return
+	}
 	c.JSON(http.StatusOK, result)
 }
 
+// GetSearchStats serves search statistics, optionally limited to searches
+// between the from and to query params (dates or RFC3339 timestamps)
 func (h *PokemonHandler) GetSearchStats(c *gin.Context) {
-	stats, err := h.service.GetSearchStats()
+	var window repository.TimeWindow
+	for _, bound := range []struct {
+		param  string
+		target **time.Time
+		endOf  bool
+	}{
+		{"from", &window.From, false},
+		{"to", &window.To, true},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseDateParam(value, bound.endOf)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   bound.param + " must be a date (YYYY-MM-DD) or RFC3339 timestamp",
+			})
+			return
+		}
+		*bound.target = &t
+	}
+
+	stats, err := h.service.GetSearchStats(window)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -78,3 +361,41 @@ func (h *PokemonHandler) GetSearchStats(c *gin.Context) {
 		"stats":   stats,
 	})
 }
+
+// ListSearches serves the search log newest first, paginated with an opaque
+// cursor taken from next_cursor of the previous page
+func (h *PokemonHandler) ListSearches(c *gin.Context) {
+	limit := parseLimit(c)
+
+	searches, next, err := h.service.ListSearches(c.Query("cursor"), limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	links := map[string]string{
+		"first": pageURL(c, nil, "cursor"),
+	}
+	var nextCursor interface{}
+	if next != "" {
+		nextCursor = next
+		links["next"] = pageURL(c, map[string]string{"cursor": next})
+	}
+	setLinkHeader(c, links)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"searches":    searches,
+			"limit":       limit,
+			"next_cursor": nextCursor,
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/session"
+)
+
+type SessionHandler struct {
+	service services.SessionService
+}
+
+func NewSessionHandler(service services.SessionService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+// GetSession serves a user's session and what it expects next
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	opts, ok := messageOptions(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetSession(c.Param("telegramId"), opts)
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// UpdateSession starts a flow, sets slots or sends an event
+func (h *SessionHandler) UpdateSession(c *gin.Context) {
+	var update services.SessionUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid session update",
+		})
+		return
+	}
+	if update.Flow == "" && update.Event == "" && len(update.Slots) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "flow, slots or event is required",
+		})
+		return
+	}
+	opts, ok := messageOptions(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.UpdateSession(c.Param("telegramId"), update, opts)
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// EndSession drops a user's session
+func (h *SessionHandler) EndSession(c *gin.Context) {
+	if err := h.service.EndSession(c.Param("telegramId")); err != nil {
+		sessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+func sessionError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	message := "Failed to update session"
+	switch {
+	case errors.Is(err, session.ErrNotFound):
+		status, message = http.StatusNotFound, "No active session; start one with a flow"
+	case errors.Is(err, services.ErrUnknownFlow), errors.Is(err, services.ErrMissingSlot):
+		status, message = http.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrInvalidEvent):
+		status, message = http.StatusConflict, err.Error()
+	case errors.Is(err, session.ErrConflict):
+		status, message = http.StatusConflict, "Session changed by another update; get it and retry"
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   message,
+	})
+}
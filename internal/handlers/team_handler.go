@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+type TeamHandler struct {
+	service services.TeamService
+}
+
+func NewTeamHandler(service services.TeamService) *TeamHandler {
+	return &TeamHandler{service: service}
+}
+
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	teams, err := h.service.ListTeams(c.Param("telegramId"))
+	if err != nil {
+		teamError(c, err, "Failed to get teams")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    teams,
+	})
+}
+
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	id, ok := teamID(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.service.GetTeam(c.Param("telegramId"), id)
+	if err != nil {
+		teamError(c, err, "Failed to get team")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    team,
+	})
+}
+
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var input services.TeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid team",
+		})
+		return
+	}
+
+	team, err := h.service.CreateTeam(c.Param("telegramId"), input)
+	if err != nil {
+		teamError(c, err, "Failed to create team")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    team,
+	})
+}
+
+// UpdateTeam replaces a team's name and members
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	id, ok := teamID(c)
+	if !ok {
+		return
+	}
+	var input services.TeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid team",
+		})
+		return
+	}
+
+	team, err := h.service.UpdateTeam(c.Param("telegramId"), id, input)
+	if err != nil {
+		teamError(c, err, "Failed to update team")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    team,
+	})
+}
+
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	id, ok := teamID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTeam(c.Param("telegramId"), id); err != nil {
+		teamError(c, err, "Failed to delete team")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// AnalyzeTeam serves a team's weaknesses, type coverage and stat totals,
+// using an older generation's chart when ?generation= is set
+func (h *TeamHandler) AnalyzeTeam(c *gin.Context) {
+	id, ok := teamID(c)
+	if !ok {
+		return
+	}
+	generation, ok := parseGeneration(c)
+	if !ok {
+		return
+	}
+
+	analysis, err := h.service.AnalyzeTeam(c.Param("telegramId"), id, generation)
+	if err != nil {
+		teamError(c, err, "Failed to analyze team")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    analysis,
+	})
+}
+
+// teamID reads the :teamId route param. A non-numeric id is answered with
+// a 400 and ok is false.
+func teamID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("teamId"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "team id must be a positive integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func teamError(c *gin.Context, err error, fallback string) {
+	status, message := http.StatusInternalServerError, fallback
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		status, message = http.StatusNotFound, "User not found"
+	case errors.Is(err, repository.ErrTeamNotFound):
+		status, message = http.StatusNotFound, "Team not found"
+	case errors.Is(err, repository.ErrTeamExists):
+		status, message = http.StatusConflict, "A team with this name already exists"
+	case errors.Is(err, services.ErrInvalidTeam):
+		status, message = http.StatusBadRequest, err.Error()
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   message,
+	})
+}
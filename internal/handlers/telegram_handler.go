@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegram"
+)
+
+type TelegramHandler struct {
+	bot    *telegram.Bot
+	secret string
+}
+
+func NewTelegramHandler(bot *telegram.Bot, secret string) *TelegramHandler {
+	return &TelegramHandler{bot: bot, secret: secret}
+}
+
+// Webhook receives Telegram updates at /telegram/webhook/:secret. A wrong
+// secret is a 404 so the route can't be probed. Once the secret matches
+// the update is always acknowledged, even when replying fails: Telegram
+// redelivers unacknowledged updates, which would repeat the replies that
+// did go out. That includes a panic in the bot, which is logged.
+func (h *TelegramHandler) Webhook(c *gin.Context) {
+	if subtle.ConstantTimeCompare([]byte(c.Param("secret")), []byte(h.secret)) != 1 {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	var update telegram.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"ok":    false,
+			"error": "Invalid update",
+		})
+		return
+	}
+
+	if err := h.handle(&update); err != nil {
+		log.Printf("Telegram update %d: %v", update.UpdateID, err)
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// handle runs the bot on an update, turning a panic into an error
+func (h *TelegramHandler) handle(update *telegram.Update) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return h.bot.HandleUpdate(update)
+}
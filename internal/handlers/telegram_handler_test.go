@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/handlers"
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokeapifake"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/supabasefake"
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegram"
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegramfake"
+)
+
+const (
+	testToken  = "test-token"
+	testSecret = "s3cret"
+)
+
+// webhookRouter routes /telegram/webhook/:secret like cmd/server, with the
+// bot talking to the fake Telegram
+func webhookRouter(t *testing.T) (*gin.Engine, *telegramfake.Server) {
+	t.Helper()
+	tg, sent := telegramfake.Start(testToken)
+	t.Cleanup(tg.Close)
+	sb, _ := supabasefake.Start()
+	t.Cleanup(sb.Close)
+	api, _, err := pokeapifake.Start(pokeapifake.Options{})
+	if err != nil {
+		t.Fatalf("start fake PokeAPI: %v", err)
+	}
+	t.Cleanup(api.Close)
+
+	users := services.NewUserService(repository.NewUserRepository(sb.URL, "key"))
+	pokemon := services.NewPokemonService(nil, services.WithBaseURL(api.URL+"/api/v2"))
+	bot := telegram.NewBot(telegram.NewClient(tg.URL, testToken), users, pokemon)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/telegram/webhook/:secret", handlers.NewTelegramHandler(bot, testSecret).Webhook)
+	return router, sent
+}
+
+const startUpdate = `{"update_id":1,"message":{"message_id":1,"from":{"id":42,"first_name":"Ash"},"chat":{"id":42,"type":"private"},"text":"/start"}}`
+
+func postUpdate(router *gin.Engine, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook/"+secret, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookSecret(t *testing.T) {
+	router, sent := webhookRouter(t)
+
+	for _, secret := range []string{"wrong", "s3cre", "s3cret2"} {
+		if w := postUpdate(router, secret, startUpdate); w.Code != http.StatusNotFound {
+			t.Errorf("secret %q = %d, want 404", secret, w.Code)
+		}
+	}
+	if n := len(sent.Sent()); n != 0 {
+		t.Fatalf("bot answered %d updates with a wrong secret", n)
+	}
+
+	if w := postUpdate(router, testSecret, startUpdate); w.Code != http.StatusOK {
+		t.Fatalf("right secret = %d, want 200", w.Code)
+	}
+	if n := len(sent.Sent()); n != 1 {
+		t.Errorf("bot sent %d messages, want the welcome", n)
+	}
+}
+
+func TestWebhookInvalidUpdate(t *testing.T) {
+	router, _ := webhookRouter(t)
+
+	if w := postUpdate(router, testSecret, "{not json"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid update = %d, want 400", w.Code)
+	}
+}
+
+func TestWebhookBotPanic(t *testing.T) {
+	tg, _ := telegramfake.Start(testToken)
+	t.Cleanup(tg.Close)
+	// Without a user service, /start panics in the bot
+	bot := telegram.NewBot(telegram.NewClient(tg.URL, testToken), nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/telegram/webhook/:secret", handlers.NewTelegramHandler(bot, testSecret).Webhook)
+
+	w := postUpdate(router, testSecret, startUpdate)
+	if w.Code != http.StatusOK || w.Body.String() != `{"ok":true}` {
+		t.Errorf("panicking update = %d %s, want 200 {\"ok\":true}", w.Code, w.Body)
+	}
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
 	"github.com/yourusername/pokemon-chatbot-api/internal/services"
 )
 
@@ -55,13 +61,19 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	telegramID := c.Param("telegramId")
 
 	user, err := h.service.GetUserByTelegramID(telegramID)
-	if err != nil {
+	if errors.Is(err, repository.ErrUserNotFound) {
 		c.JSON(http.StatusOK, gin.H{
 			"exists": false,
 			"user":   nil,
 		})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"exists": true,
@@ -69,6 +81,51 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	})
 }
 
+type LanguageRequest struct {
+	Language string `json:"language" binding:"required"`
+}
+
+// SetLanguage stores the language the bot answers a user in
+func (h *UserHandler) SetLanguage(c *gin.Context) {
+	var req LanguageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "language is required",
+		})
+		return
+	}
+	lang, ok := i18n.Normalize(req.Language)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "language must be one of " + strings.Join(i18n.Supported(), ", "),
+		})
+		return
+	}
+
+	user, err := h.service.SetLanguage(c.Param("telegramId"), lang)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "User not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"user":    user,
+	})
+}
+
 func (h *UserHandler) CheckRegistration(c *gin.Context) {
 	telegramID := c.Param("telegramId")
 
@@ -85,25 +142,32 @@ func (h *UserHandler) CheckRegistration(c *gin.Context) {
 	})
 }
 
+// ListUsers serves the dashboard user list. Passing a cursor param (empty for
+// the first page) switches to keyset pagination; page/limit is kept for
+// existing clients.
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	// Get pagination params
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
+	limit := parseLimit(c)
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+	opts, err := parseUserListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 10
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.listUsersByCursor(c, opts, cursor, limit)
+		return
 	}
-	if limit > 100 {
-		limit = 100
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
 	}
 
-	users, total, err := h.service.GetUsersPaginated(page, limit)
+	users, total, err := h.service.GetUsersPaginated(opts, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -114,6 +178,20 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 	totalPages := (total + limit - 1) / limit
 
+	links := map[string]string{
+		"first": pageURL(c, map[string]string{"page": "1"}),
+	}
+	if page > 1 {
+		links["prev"] = pageURL(c, map[string]string{"page": strconv.Itoa(page - 1)})
+	}
+	if page < totalPages {
+		links["next"] = pageURL(c, map[string]string{"page": strconv.Itoa(page + 1)})
+	}
+	if totalPages > 0 {
+		links["last"] = pageURL(c, map[string]string{"page": strconv.Itoa(totalPages)})
+	}
+	setLinkHeader(c, links)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
@@ -125,3 +203,104 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		},
 	})
 }
+
+func (h *UserHandler) listUsersByCursor(c *gin.Context, opts repository.UserListOptions, cursor string, limit int) {
+	users, next, err := h.service.GetUsersAfter(opts, cursor, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	links := map[string]string{
+		"first": pageURL(c, map[string]string{"cursor": ""}, "page"),
+	}
+	if next != "" {
+		links["next"] = pageURL(c, map[string]string{"cursor": next}, "page")
+	}
+	setLinkHeader(c, links)
+
+	var nextCursor interface{}
+	if next != "" {
+		nextCursor = next
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"users":       users,
+			"limit":       limit,
+			"next_cursor": nextCursor,
+		},
+	})
+}
+
+// parseUserListOptions reads the q, registered_from, registered_to,
+// active_since, sort and order query params of the user list
+func parseUserListOptions(c *gin.Context) (repository.UserListOptions, error) {
+	opts := repository.UserListOptions{
+		Query:  strings.TrimSpace(c.Query("q")),
+		SortBy: c.DefaultQuery("sort", repository.SortRegisteredAt),
+	}
+
+	if !repository.IsValidUserSort(opts.SortBy) {
+		return opts, fmt.Errorf("sort must be one of registered_at, last_active, first_name")
+	}
+
+	switch strings.ToLower(c.Query("order")) {
+	case "asc":
+		opts.SortAsc = true
+	case "desc":
+		opts.SortAsc = false
+	case "":
+		// Names read naturally A-Z, timestamps newest first
+		opts.SortAsc = opts.SortBy == repository.SortFirstName
+	default:
+		return opts, fmt.Errorf("order must be asc or desc")
+	}
+
+	dates := []struct {
+		param  string
+		target **time.Time
+		endOf  bool
+	}{
+		{"registered_from", &opts.RegisteredFrom, false},
+		{"registered_to", &opts.RegisteredTo, true},
+		{"active_since", &opts.ActiveSince, false},
+	}
+	for _, d := range dates {
+		value := c.Query(d.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseDateParam(value, d.endOf)
+		if err != nil {
+			return opts, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC3339 timestamp", d.param)
+		}
+		*d.target = &t
+	}
+
+	return opts, nil
+}
+
+// parseDateParam accepts RFC3339 timestamps or plain dates. A plain date used
+// as an upper bound covers the whole day.
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,139 @@
+// Package i18n holds the bot's message catalogs and picks the language of a
+// request. Messages are translated into the catalog languages; Pokemon,
+// type and ability names come from PokeAPI, which covers more languages, so
+// those are accepted too and fall back to English messages.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the language used when nothing else applies
+const Default = "en"
+
+//go:embed catalogs/*.json
+var files embed.FS
+
+// catalogs maps a language to its messages, keyed like "pokemon.found".
+// Values are fmt formats.
+var catalogs = loadCatalogs()
+
+// nameLanguages are the PokeAPI language codes with localized names
+var nameLanguages = []string{
+	"ja-Hrkt", "roomaji", "ko", "zh-Hant", "fr", "de", "es", "it", "en",
+	"cs", "ja", "zh-Hans", "pt-BR",
+}
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := files.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		raw, err := files.ReadFile(path.Join("catalogs", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: catalog %s: %v", entry.Name(), err))
+		}
+		out[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return out
+}
+
+// Catalogs lists the languages with translated messages, sorted
+func Catalogs() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Supported lists every accepted language: the catalogs, then the PokeAPI
+// name languages
+func Supported() []string {
+	langs := Catalogs()
+	for _, lang := range nameLanguages {
+		if _, ok := catalogs[lang]; !ok {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// Normalize returns the supported code for a language tag, matching case
+// insensitively and falling back to the base language ("id-ID" is "id").
+// ok is false when neither is supported.
+func Normalize(tag string) (string, bool) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", false
+	}
+	for _, candidate := range []string{tag, strings.SplitN(tag, "-", 2)[0]} {
+		for _, lang := range Supported() {
+			if strings.EqualFold(lang, candidate) {
+				return lang, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FromAcceptLanguage picks the supported language with the highest quality
+// in an Accept-Language header, "" when none is supported
+func FromAcceptLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if lang, ok := Normalize(tag); ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// T formats the message key in lang, falling back to the default language
+// and then to the key itself
+func T(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[Default][key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// HasNames reports whether PokeAPI has Pokemon, type and ability names in
+// lang
+func HasNames(lang string) bool {
+	for _, l := range nameLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
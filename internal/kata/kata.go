@@ -0,0 +1,92 @@
+// Package kata builds Kata Platform bot messages, so flows can show API
+// results without mapping each field. Messages have the shape of Kata's
+// text and template actions:
+//
+//	{"type": "text", "options": {"text": "..."}}
+//	{"type": "template", "options": {"type": "image", "items": {...}}}
+package kata
+
+// Response is what the API returns in Kata mode
+type Response struct {
+	Found    bool      `json:"found"`
+	Messages []Message `json:"messages"`
+}
+
+// Message is one bot message
+type Message struct {
+	Type    string  `json:"type"`
+	Options Options `json:"options"`
+}
+
+// Options holds the text of a text message, or the template type and its
+// items: an object for images and buttons, a list for carousels
+type Options struct {
+	Text  string      `json:"text,omitempty"`
+	Type  string      `json:"type,omitempty"`
+	Items interface{} `json:"items,omitempty"`
+}
+
+// Image is the items of an image template
+type Image struct {
+	OriginalContentURL string `json:"originalContentUrl"`
+	PreviewImageURL    string `json:"previewImageUrl"`
+}
+
+// Buttons is the items of a button template
+type Buttons struct {
+	Text    string   `json:"text"`
+	Actions []Action `json:"actions"`
+}
+
+// Card is one column of a carousel
+type Card struct {
+	Title             string   `json:"title"`
+	Text              string   `json:"text"`
+	ThumbnailImageURL string   `json:"thumbnailImageUrl,omitempty"`
+	Actions           []Action `json:"actions"`
+}
+
+// Action is a button. A text action sends Text as if the user typed it (a
+// quick reply); a postback action sends Payload to the flow.
+type Action struct {
+	Type    string            `json:"type"`
+	Label   string            `json:"label"`
+	Text    string            `json:"text,omitempty"`
+	Payload map[string]string `json:"payload,omitempty"`
+}
+
+// Text is a plain text message
+func Text(text string) Message {
+	return Message{Type: "text", Options: Options{Text: text}}
+}
+
+// ImageMessage shows the image at url
+func ImageMessage(url string) Message {
+	return Message{Type: "template", Options: Options{
+		Type:  "image",
+		Items: Image{OriginalContentURL: url, PreviewImageURL: url},
+	}}
+}
+
+// ButtonsMessage is text with buttons under it
+func ButtonsMessage(text string, actions []Action) Message {
+	return Message{Type: "template", Options: Options{
+		Type:  "button",
+		Items: Buttons{Text: text, Actions: actions},
+	}}
+}
+
+// Carousel is a row of cards
+func Carousel(cards []Card) Message {
+	return Message{Type: "template", Options: Options{Type: "carousel", Items: cards}}
+}
+
+// QuickReply is a button that sends text
+func QuickReply(label, text string) Action {
+	return Action{Type: "text", Label: label, Text: text}
+}
+
+// Postback is a button that sends payload to the flow
+func Postback(label string, payload map[string]string) Action {
+	return Action{Type: "postback", Label: label, Payload: payload}
+}
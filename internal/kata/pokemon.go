@@ -0,0 +1,86 @@
+package kata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+// maxButtons is the most actions a button template takes on the channels
+// Kata relays to (LINE allows four)
+const maxButtons = 4
+
+// Pokemon is a lookup as messages: the text and the sprite, or the
+// not-found text with quick replies for close names
+func Pokemon(result *services.PokemonResponse, lang string) Response {
+	if !result.Found {
+		messages := []Message{Text(result.Message)}
+		if len(result.Suggestions) > 0 {
+			actions := make([]Action, 0, len(result.Suggestions))
+			for _, s := range result.Suggestions {
+				actions = append(actions, QuickReply(s.Name, s.Pokemon))
+			}
+			messages = append(messages, ButtonsMessage(i18n.T(lang, "kata.did_you_mean"), limit(actions)))
+		}
+		return Response{Found: false, Messages: messages}
+	}
+
+	messages := []Message{Text(result.Message)}
+	if result.Data != nil && result.Data.Sprite != "" {
+		messages = append(messages, ImageMessage(result.Data.Sprite))
+	}
+	return Response{Found: true, Messages: messages}
+}
+
+// Compare is a comparison as a carousel of the Pokemon followed by the
+// summary. When some names weren't found, each close name is offered as a
+// button whose payload is the requested names with that one corrected.
+func Compare(result *services.CompareResponse, names []string, lang string) Response {
+	if !result.Found {
+		messages := []Message{Text(result.Message)}
+		var actions []Action
+		for _, missing := range result.NotFound {
+			for _, s := range result.Suggestions[missing] {
+				corrected := make([]string, len(names))
+				for i, name := range names {
+					if name == missing {
+						name = s.Pokemon
+					}
+					corrected[i] = name
+				}
+				actions = append(actions, Postback(s.Name, map[string]string{"names": strings.Join(corrected, ",")}))
+			}
+		}
+		if len(actions) > 0 {
+			messages = append(messages, ButtonsMessage(i18n.T(lang, "kata.did_you_mean"), limit(actions)))
+		}
+		return Response{Found: false, Messages: messages}
+	}
+
+	cards := make([]Card, len(result.Data.Pokemon))
+	for i, p := range result.Data.Pokemon {
+		types := make([]string, len(p.Types))
+		for j, t := range p.Types {
+			types[j] = strings.ToUpper(t[:1]) + t[1:]
+		}
+		cards[i] = Card{
+			Title:             fmt.Sprintf("%s #%d", p.Name, p.ID),
+			Text:              fmt.Sprintf("%s · Total %d", strings.Join(types, ", "), p.Total),
+			ThumbnailImageURL: p.Sprite,
+			Actions: []Action{
+				Postback(i18n.T(lang, "kata.details"), map[string]string{"pokemon": strconv.Itoa(p.ID)}),
+			},
+		}
+	}
+	return Response{Found: true, Messages: []Message{Carousel(cards), Text(result.Message)}}
+}
+
+func limit(actions []Action) []Action {
+	if len(actions) > maxButtons {
+		return actions[:maxButtons]
+	}
+	return actions
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS pokemon_searches (
+    id SERIAL PRIMARY KEY,
+    pokemon_name VARCHAR(255) NOT NULL,
+    pokemon_id INTEGER,
+    found BOOLEAN DEFAULT true,
+    searched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pokemon_name ON pokemon_searches(pokemon_name);
+CREATE INDEX IF NOT EXISTS idx_searched_at ON pokemon_searches(searched_at DESC);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS favorites (
+    id SERIAL PRIMARY KEY,
+    telegram_id VARCHAR(255) NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    pokemon_name VARCHAR(255) NOT NULL,
+    pokemon_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (telegram_id, pokemon_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_pokemon_name ON favorites(pokemon_name);
+CREATE INDEX IF NOT EXISTS idx_favorites_created_at ON favorites(created_at DESC);
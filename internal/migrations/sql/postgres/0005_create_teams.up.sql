@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    telegram_id VARCHAR(255) NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    members JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (telegram_id, name)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    telegram_id TEXT UNIQUE NOT NULL,
+    username TEXT,
+    first_name TEXT NOT NULL,
+    last_name TEXT,
+    registered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_active TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_telegram_id ON users(telegram_id);
+CREATE INDEX IF NOT EXISTS idx_users_registered_at ON users(registered_at DESC, id DESC);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS pokemon_searches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pokemon_name TEXT NOT NULL,
+    pokemon_id INTEGER,
+    found BOOLEAN DEFAULT 1,
+    searched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pokemon_name ON pokemon_searches(pokemon_name);
+CREATE INDEX IF NOT EXISTS idx_searched_at ON pokemon_searches(searched_at DESC);
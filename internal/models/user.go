@@ -13,6 +13,8 @@ type User struct {
 	LastName     string     `json:"last_name,omitempty"`
 	RegisteredAt *time.Time `json:"registered_at,omitempty" gorm:"default:CURRENT_TIMESTAMP"`
 	LastActive   *time.Time `json:"last_active,omitempty" gorm:"default:CURRENT_TIMESTAMP"`
+	// Language is the preferred bot language, empty for none
+	Language string `json:"language,omitempty"`
 }
 
 // UnmarshalJSON handles multiple timestamp formats
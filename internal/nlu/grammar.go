@@ -0,0 +1,142 @@
+package nlu
+
+import "github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+
+// Keyword weights: strong keywords name the intent on their own, weak ones
+// only lean towards it
+const (
+	strong = 1.0
+	weak   = 0.6
+)
+
+// rule is how an intent is recognized: its keywords (phrases of lowercase
+// words, English and Indonesian alike) and the entities it needs
+type rule struct {
+	intent   string
+	keywords map[string]float64
+	pokemon  int
+	types    int
+	// fallback is the keyword evidence assumed when the entities fit but
+	// no keyword matched, so "pikachu" alone is still a lookup
+	fallback float64
+}
+
+var matchupKeywords = map[string]float64{
+	"weak": strong, "weakness": strong, "weaknesses": strong, "weak to": strong,
+	"strong against": strong, "effective": strong, "super effective": strong,
+	"counter": strong, "counters": strong, "resist": strong, "resists": strong,
+	"resistant": strong, "immune": strong, "matchup": strong, "matchups": strong,
+	"beat": weak, "beats": weak, "good against": weak,
+	"lemah": strong, "kelemahan": strong, "efektif": strong, "kebal": strong,
+	"kuat": weak, "tahan": weak,
+}
+
+// rules in order of precedence when two intents score the same
+var rules = []rule{
+	{
+		intent: IntentCompare,
+		keywords: map[string]float64{
+			"compare": strong, "comparison": strong, "vs": strong, "versus": strong,
+			"better": weak, "stronger": weak, "difference": weak,
+			"bandingkan": strong, "banding": strong, "dibanding": strong,
+			"bandingin": strong, "lebih kuat": weak,
+		},
+		pokemon:  2,
+		fallback: 0.4,
+	},
+	{
+		intent: IntentEvolution,
+		keywords: map[string]float64{
+			"evolve": strong, "evolves": strong, "evolved": strong, "evolution": strong,
+			"evolutions": strong, "evo": strong,
+			"evolusi": strong, "berevolusi": strong,
+		},
+		pokemon: 1,
+	},
+	{
+		intent: IntentMoves,
+		keywords: map[string]float64{
+			"moves": strong, "moveset": strong, "learn": strong, "learns": strong,
+			"move": weak, "attacks": weak,
+			"jurus": strong, "gerakan": strong, "serangan": weak,
+		},
+		pokemon: 1,
+	},
+	{
+		intent:   IntentMatchups,
+		keywords: matchupKeywords,
+		pokemon:  1,
+	},
+	{
+		intent:   IntentTypeInfo,
+		keywords: matchupKeywords,
+		types:    1,
+	},
+	{
+		intent: IntentPokemonInfo,
+		keywords: map[string]float64{
+			"info": strong, "information": strong, "look up": strong, "lookup": strong,
+			"show": weak, "about": weak, "tell": weak, "what is": weak, "who is": weak,
+			"search": weak, "find": weak, "picture": weak, "photo": weak,
+			"lihat": weak, "tampilkan": weak, "cari": weak, "tentang": weak, "gambar": weak,
+		},
+		pokemon:  1,
+		fallback: 0.5,
+	},
+	{
+		intent: IntentRegister,
+		keywords: map[string]float64{
+			"register": strong, "sign up": strong, "signup": strong, "join": weak,
+			"daftar": strong, "mendaftar": strong, "registrasi": strong,
+		},
+	},
+	{
+		intent: IntentGreeting,
+		keywords: map[string]float64{
+			"hi": strong, "hello": strong, "hey": strong, "good morning": strong,
+			"halo": strong, "hai": strong, "selamat pagi": strong,
+			"selamat siang": strong, "selamat malam": strong,
+		},
+	},
+	{
+		intent: IntentHelp,
+		keywords: map[string]float64{
+			"help": strong, "what can you do": strong, "commands": strong,
+			"bantuan": strong, "bantu": strong, "tolong": weak,
+		},
+	},
+}
+
+// stopwords never start or join a Pokemon name, so "new" isn't read as
+// Mew and "and" doesn't glue two names together
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "me": true, "my": true, "i": true,
+	"you": true, "your": true, "is": true, "are": true, "was": true, "be": true,
+	"what": true, "whats": true, "s": true, "of": true, "for": true, "to": true,
+	"in": true, "on": true, "with": true, "and": true, "or": true, "please": true,
+	"pls": true, "can": true, "could": true, "would": true, "do": true,
+	"does": true, "how": true, "which": true, "who": true, "give": true,
+	"get": true, "some": true, "any": true, "it": true, "its": true,
+	"this": true, "that": true, "than": true, "more": true, "there": true,
+	"against": true, "into": true, "at": true, "by": true, "from": true,
+	"pokemon": true, "type": true, "types": true, "tipe": true,
+	"yang": true, "dan": true, "apa": true, "itu": true, "ini": true,
+	"dengan": true, "atau": true, "aku": true, "saya": true, "kamu": true,
+	"dong": true, "ya": true, "sih": true, "si": true, "untuk": true,
+	"dari": true, "ke": true, "di": true, "mana": true, "siapa": true,
+	"lebih": true, "mau": true, "ingin": true,
+}
+
+// typeWords are the Indonesian names players use for types, only read in
+// Indonesian messages since words like "air" mean something else in
+// English
+var typeWords = map[string]map[string]string{
+	"id": {
+		"petarung": typechart.Fighting, "terbang": typechart.Flying,
+		"racun": typechart.Poison, "tanah": typechart.Ground, "batu": typechart.Rock,
+		"serangga": typechart.Bug, "hantu": typechart.Ghost, "baja": typechart.Steel,
+		"api": typechart.Fire, "air": typechart.Water, "rumput": typechart.Grass,
+		"listrik": typechart.Electric, "psikis": typechart.Psychic, "es": typechart.Ice,
+		"naga": typechart.Dragon, "gelap": typechart.Dark, "peri": typechart.Fairy,
+	},
+}
@@ -0,0 +1,294 @@
+// Package nlu reads what a chat message asks for: it classifies the
+// message into one of the intents the API can answer and extracts the
+// Pokemon and types it mentions. It uses a keyword grammar rather than a
+// trained model, so results are predictable and need no external service.
+// Telling which words name a Pokemon is left to a Resolver, which services
+// backs with the Pokemon name index.
+package nlu
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+)
+
+// Intents a message can have
+const (
+	IntentPokemonInfo = "pokemon_info"
+	IntentMatchups    = "pokemon_matchups"
+	IntentTypeInfo    = "type_info"
+	IntentCompare     = "compare_pokemon"
+	IntentEvolution   = "evolution"
+	IntentMoves       = "moves"
+	IntentRegister    = "register"
+	IntentGreeting    = "greeting"
+	IntentHelp        = "help"
+	// IntentUnknown is answered when no intent is confident enough
+	IntentUnknown = "unknown"
+)
+
+// Entity types
+const (
+	EntityPokemon = "pokemon"
+	EntityType    = "type"
+)
+
+// MinConfidence is the confidence an intent needs to be picked
+const MinConfidence = 0.35
+
+// maxNameWords is the most words a Pokemon name spans ("tapu koko", "mr
+// mime jr")
+const maxNameWords = 3
+
+// Resolver finds the Pokemon a phrase of lowercase words names. score is 1
+// for an exact or localized name and lower the more the phrase had to be
+// corrected.
+type Resolver func(phrase string) (name string, score float64, ok bool)
+
+// Intent is a candidate meaning of a message. Missing names the entity the
+// intent still needs, so the bot can ask for it.
+type Intent struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+	Missing    string  `json:"missing,omitempty"`
+}
+
+// Entity is a Pokemon or type mentioned in a message. Value is its PokeAPI
+// name (or the Pokedex number given), Text the words it was read from.
+type Entity struct {
+	Type       string  `json:"type"`
+	Value      string  `json:"value"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Result is what Parse read from a message. Intents lists every candidate,
+// best first; Intent is the best one, or IntentUnknown when none reaches
+// MinConfidence.
+type Result struct {
+	Text     string   `json:"text"`
+	Lang     string   `json:"lang"`
+	Intent   Intent   `json:"intent"`
+	Intents  []Intent `json:"intents"`
+	Entities []Entity `json:"entities"`
+}
+
+// Parse classifies text, a message in lang, resolving Pokemon names with
+// resolve
+func Parse(text, lang string, resolve Resolver) *Result {
+	words := tokenize(text)
+	used := make([]bool, len(words))
+	hits := matchKeywords(words, used)
+	entities := extractEntities(words, used, lang, resolve)
+
+	// Fallbacks only apply when no keyword asked about Pokemon, so
+	// "compare mew" asks for the second Pokemon instead of showing Mew
+	fallback := true
+	for _, r := range rules {
+		if hits[r.intent] > 0 && (r.pokemon > 0 || r.types > 0) {
+			fallback = false
+		}
+	}
+
+	intents := make([]Intent, 0, len(rules))
+	for _, r := range rules {
+		if intent := r.score(hits[r.intent], entities, fallback); intent.Confidence > 0 {
+			intents = append(intents, intent)
+		}
+	}
+	sort.SliceStable(intents, func(i, j int) bool {
+		return intents[i].Confidence > intents[j].Confidence
+	})
+
+	result := &Result{
+		Text:     text,
+		Lang:     lang,
+		Intent:   Intent{Name: IntentUnknown},
+		Intents:  intents,
+		Entities: entities,
+	}
+	if len(intents) > 0 && intents[0].Confidence >= MinConfidence {
+		result.Intent = intents[0]
+	}
+	return result
+}
+
+// tokenize lowercases text and splits it into words, keeping hyphens
+// ("ho-oh") and the # of Pokedex numbers
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '#'
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if f = strings.Trim(f, "-"); f != "" {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// matchKeywords returns the weight of the strongest keyword of each intent
+// found in words, and marks the keyword words as used
+func matchKeywords(words []string, used []bool) map[string]float64 {
+	hits := make(map[string]float64)
+	for _, r := range rules {
+		for phrase, weight := range r.keywords {
+			keyword := strings.Fields(phrase)
+			for i := 0; i+len(keyword) <= len(words); i++ {
+				if !equalWords(words[i:i+len(keyword)], keyword) {
+					continue
+				}
+				hits[r.intent] = math.Max(hits[r.intent], weight)
+				for j := range keyword {
+					used[i+j] = true
+				}
+			}
+		}
+	}
+	return hits
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// extractEntities reads Pokedex numbers, types and Pokemon names, in
+// message order, from the words that aren't keywords. Names are tried
+// longest first so "mr mime" isn't read as two words.
+func extractEntities(words []string, used []bool, lang string, resolve Resolver) []Entity {
+	free := func(i int) bool {
+		return !used[i] && !stopwords[words[i]]
+	}
+
+	entities := []Entity{}
+	for i := 0; i < len(words); i++ {
+		if !free(i) {
+			continue
+		}
+		if entity, ok := number(words[i]); ok {
+			entities = append(entities, entity)
+			continue
+		}
+		if t, ok := typeOf(words[i], lang); ok {
+			entities = append(entities, Entity{Type: EntityType, Value: t, Text: words[i], Confidence: 1})
+			continue
+		}
+
+		for n := min(maxNameWords, len(words)-i); n > 0; n-- {
+			span := words[i : i+n]
+			if !allFree(i, n, free) {
+				continue
+			}
+			phrase := strings.Join(span, " ")
+			if name, score, ok := resolve(phrase); ok {
+				entities = append(entities, Entity{Type: EntityPokemon, Value: name, Text: phrase, Confidence: round(score)})
+				i += n - 1
+				break
+			}
+		}
+	}
+	return entities
+}
+
+func allFree(start, n int, free func(int) bool) bool {
+	for i := start; i < start+n; i++ {
+		if !free(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// number reads "#25" or "25" as a Pokedex number. Bare numbers are less
+// certain: they may be a count or a generation.
+func number(word string) (Entity, bool) {
+	digits := strings.TrimPrefix(word, "#")
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 1 || len(digits) > 4 {
+		return Entity{}, false
+	}
+	confidence := 0.8
+	if digits != word {
+		confidence = 1
+	}
+	return Entity{Type: EntityPokemon, Value: strconv.Itoa(n), Text: word, Confidence: confidence}, true
+}
+
+// typeOf reads a type name, in English or in lang
+func typeOf(word, lang string) (string, bool) {
+	word = strings.TrimSuffix(word, "-type")
+	if typechart.Latest().Has(word) {
+		return word, true
+	}
+	t, ok := typeWords[lang][word]
+	return t, ok
+}
+
+// score rates how well a message with the given keyword weight and
+// entities fits the rule. Keywords count for 60%, how surely the needed
+// entities were recognized for 40%; an intent lacking entities keeps part
+// of its keyword score so it can still win and ask for them. Intents
+// without entities lose half when the message names Pokemon or types, as
+// it then most likely asks about those.
+func (r rule) score(keyword float64, entities []Entity, fallback bool) Intent {
+	intent := Intent{Name: r.intent}
+
+	if r.pokemon == 0 && r.types == 0 {
+		confidence := keyword * 0.9
+		if len(entities) > 0 {
+			confidence /= 2
+		}
+		intent.Confidence = round(confidence)
+		return intent
+	}
+
+	fit, missing := r.fit(entities)
+	switch {
+	case missing != "":
+		intent.Missing = missing
+		intent.Confidence = round(keyword * 0.6 * 0.7)
+	case keyword > 0:
+		intent.Confidence = round(keyword*0.6 + fit*0.4)
+	case fallback && r.fallback > 0:
+		intent.Confidence = round(r.fallback*0.6 + fit*0.4)
+	}
+	return intent
+}
+
+// fit is the mean confidence of the entities the rule needs, or the type
+// of entity there aren't enough of
+func (r rule) fit(entities []Entity) (float64, string) {
+	var sum float64
+	var pokemon, types int
+	for _, e := range entities {
+		switch {
+		case e.Type == EntityPokemon && r.pokemon > 0:
+			pokemon++
+			sum += e.Confidence
+		case e.Type == EntityType && r.types > 0:
+			types++
+			sum += e.Confidence
+		}
+	}
+	if pokemon < r.pokemon {
+		return 0, EntityPokemon
+	}
+	if types < r.types {
+		return 0, EntityType
+	}
+	return sum / float64(pokemon+types), ""
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,109 @@
+package nlu_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/nlu"
+)
+
+// resolve knows a few names exactly and one misspelling
+func resolve(phrase string) (string, float64, bool) {
+	switch phrase {
+	case "pikachu", "mew", "eevee":
+		return phrase, 1, true
+	case "mr mime":
+		return "mr-mime", 1, true
+	case "pikachoo":
+		return "pikachu", 0.7, true
+	}
+	return "", 0, false
+}
+
+func TestParseIntent(t *testing.T) {
+	tests := []struct {
+		text, lang string
+		intent     string
+		confidence float64
+		missing    string
+	}{
+		// A name alone falls back to a lookup: 0.5*0.6 + 1*0.4
+		{"pikachu", "en", nlu.IntentPokemonInfo, 0.7, ""},
+		// A corrected name lowers the entity part: 0.3 + 0.7*0.4
+		{"pikachoo", "en", nlu.IntentPokemonInfo, 0.58, ""},
+		{"compare pikachu vs mew", "en", nlu.IntentCompare, 1, ""},
+		// Missing the second Pokemon keeps 70% of the keyword part, enough
+		// to ask for it
+		{"compare mew", "en", nlu.IntentCompare, 0.42, nlu.EntityPokemon},
+		// Matchups and type info tie; matchups come first in the grammar
+		{"is pikachu weak to ground", "en", nlu.IntentMatchups, 1, ""},
+		{"what is fire weak to", "en", nlu.IntentTypeInfo, 1, ""},
+		{"how does eevee evolve", "en", nlu.IntentEvolution, 1, ""},
+		{"kelemahan pikachu apa", "id", nlu.IntentMatchups, 1, ""},
+		{"hello", "en", nlu.IntentGreeting, 0.9, ""},
+		// Naming a Pokemon halves intents without entities
+		{"hello pikachu", "en", nlu.IntentPokemonInfo, 0.7, ""},
+		{"daftar", "id", nlu.IntentRegister, 0.9, ""},
+		// A weak keyword without its Pokemon stays below MinConfidence
+		{"tell me a joke", "en", nlu.IntentUnknown, 0, ""},
+		{"", "en", nlu.IntentUnknown, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := nlu.Parse(tt.text, tt.lang, resolve).Intent
+			want := nlu.Intent{Name: tt.intent, Confidence: tt.confidence, Missing: tt.missing}
+			if got != want {
+				t.Errorf("Parse(%q) intent = %+v, want %+v", tt.text, got, want)
+			}
+		})
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	// Unknown still lists the candidates below the threshold
+	result := nlu.Parse("tell me a joke", "en", resolve)
+	if len(result.Intents) == 0 || result.Intents[0].Confidence >= nlu.MinConfidence {
+		t.Errorf("intents = %+v, want candidates below %v", result.Intents, nlu.MinConfidence)
+	}
+	for i := 1; i < len(result.Intents); i++ {
+		if result.Intents[i].Confidence > result.Intents[i-1].Confidence {
+			t.Errorf("intents aren't sorted: %+v", result.Intents)
+		}
+	}
+}
+
+func TestParseEntities(t *testing.T) {
+	tests := []struct {
+		text, lang string
+		want       []nlu.Entity
+	}{
+		{"#25", "en", []nlu.Entity{{Type: nlu.EntityPokemon, Value: "25", Text: "#25", Confidence: 1}}},
+		// Bare numbers may be something else
+		{"025", "en", []nlu.Entity{{Type: nlu.EntityPokemon, Value: "25", Text: "025", Confidence: 0.8}}},
+		{"0 or 12345", "en", []nlu.Entity{}},
+		{"Water-type vs FIRE", "en", []nlu.Entity{
+			{Type: nlu.EntityType, Value: "water", Text: "water-type", Confidence: 1},
+			{Type: nlu.EntityType, Value: "fire", Text: "fire", Confidence: 1},
+		}},
+		// Indonesian type names only count in Indonesian
+		{"api dan air", "id", []nlu.Entity{
+			{Type: nlu.EntityType, Value: "fire", Text: "api", Confidence: 1},
+			{Type: nlu.EntityType, Value: "water", Text: "air", Confidence: 1},
+		}},
+		{"air", "en", []nlu.Entity{}},
+		// Longest names first, and stopwords don't join names
+		{"Mr. Mime and pikachoo", "en", []nlu.Entity{
+			{Type: nlu.EntityPokemon, Value: "mr-mime", Text: "mr mime", Confidence: 1},
+			{Type: nlu.EntityPokemon, Value: "pikachu", Text: "pikachoo", Confidence: 0.7},
+		}},
+		// Keywords aren't read as names
+		{"compare mew", "en", []nlu.Entity{{Type: nlu.EntityPokemon, Value: "mew", Text: "mew", Confidence: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := nlu.Parse(tt.text, tt.lang, resolve).Entities; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) entities = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Package pokeapifake is a local stand-in for the PokeAPI v2 REST API. It
+// serves recorded fixture JSON so the lookup path can be exercised without
+// reaching pokeapi.co, with optional latency and error injection.
+package pokeapifake
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// upstreamURL is the base recorded in fixture URLs; it is rewritten to the
+// fake's own address when serving
+const upstreamURL = "https://pokeapi.co/api/v2"
+
+// Resources served by the fake, named after their PokeAPI endpoints
+var Resources = []string{"pokemon", "pokemon-species", "type", "ability", "evolution-chain", "move"}
+
+// Options configures a Server. The zero value serves the embedded fixtures
+// with no latency and no errors.
+type Options struct {
+	// Fixtures overrides the embedded fixtures. It must hold one
+	// <resource>/<name>.json file per document.
+	Fixtures fs.FS
+	// Latency delays every response
+	Latency time.Duration
+	// ErrorRate is the fraction of requests, between 0 and 1, answered
+	// with 500
+	ErrorRate float64
+	// Errors answers requests to the given paths (e.g. "pokemon/25") with
+	// a fixed status code
+	Errors map[string]int
+	// Seed makes ErrorRate deterministic when not zero
+	Seed int64
+}
+
+// Server serves fixtures under /api/v2. It implements http.Handler.
+type Server struct {
+	// resources maps resource -> name -> raw JSON
+	resources map[string]map[string][]byte
+	// ids maps resource -> id -> name
+	ids map[string]map[string]string
+
+	mu        sync.Mutex
+	latency   time.Duration
+	errorRate float64
+	errors    map[string]int
+	rand      *rand.Rand
+
+	requests atomic.Int64
+}
+
+// New loads the fixtures and returns a Server
+func New(opts Options) (*Server, error) {
+	fsys := opts.Fixtures
+	if fsys == nil {
+		sub, err := fs.Sub(fixtures, "fixtures")
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	s := &Server{
+		resources: make(map[string]map[string][]byte),
+		ids:       make(map[string]map[string]string),
+		latency:   opts.Latency,
+		errorRate: opts.ErrorRate,
+		errors:    make(map[string]int),
+		rand:      rand.New(rand.NewSource(seed)),
+	}
+	for p, status := range opts.Errors {
+		s.errors[strings.Trim(p, "/")] = status
+	}
+
+	if err := s.load(fsys); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Start runs the fake on a local port and returns the httptest server. Its
+// URL plus "/api/v2" is the base URL to hand to the service.
+func Start(opts Options) (*httptest.Server, *Server, error) {
+	s, err := New(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return httptest.NewServer(s), s, nil
+}
+
+func (s *Server) load(fsys fs.FS) error {
+	for _, resource := range Resources {
+		entries, err := fs.ReadDir(fsys, resource)
+		if err != nil {
+			// Every resource is optional
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			file := path.Join(resource, entry.Name())
+			raw, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return err
+			}
+
+			var doc struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(raw, &doc); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+
+			id := strconv.Itoa(doc.ID)
+			name := doc.Name
+			if name == "" {
+				// Evolution chains have no name, only an id
+				name = id
+			}
+
+			if s.resources[resource] == nil {
+				s.resources[resource] = make(map[string][]byte)
+				s.ids[resource] = make(map[string]string)
+			}
+			s.resources[resource][name] = raw
+			if doc.ID != 0 {
+				s.ids[resource][id] = name
+			}
+		}
+	}
+	return nil
+}
+
+// FailPath makes requests to path (e.g. "pokemon/pikachu") answer status.
+// A zero status clears the failure.
+func (s *Server) FailPath(p string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p = strings.Trim(p, "/")
+	if status == 0 {
+		delete(s.errors, p)
+		return
+	}
+	s.errors[p] = status
+}
+
+// SetLatency changes the delay applied to every response
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetErrorRate changes the fraction of requests answered with 500
+func (s *Server) SetErrorRate(rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorRate = rate
+}
+
+// Requests returns the number of requests served so far
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// Names returns the sorted names of every fixture of a resource
+func (s *Server) Names(resource string) []string {
+	names := make([]string, 0, len(s.resources[resource]))
+	for name := range s.resources[resource] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	p, ok := strings.CutPrefix(r.URL.Path, "/api/v2/")
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	p = strings.Trim(p, "/")
+
+	s.mu.Lock()
+	latency := s.latency
+	status, failing := s.errors[p]
+	randomFailure := s.errorRate > 0 && s.rand.Float64() < s.errorRate
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if failing {
+		writeError(w, status)
+		return
+	}
+	if randomFailure {
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	resource, key, _ := strings.Cut(p, "/")
+	if _, known := s.resources[resource]; !known {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	base := "http://" + r.Host + "/api/v2"
+	if key == "" {
+		s.serveList(w, r, resource, base)
+		return
+	}
+
+	key = strings.ToLower(key)
+	if name, ok := s.ids[resource][key]; ok {
+		key = name
+	}
+	raw, ok := s.resources[resource][key]
+	if !ok {
+		// PokeAPI answers unknown resources with a plain-text 404
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(strings.ReplaceAll(string(raw), upstreamURL, base)))
+}
+
+// serveList answers /api/v2/<resource>?limit=&offset= in PokeAPI's
+// paginated list format
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, resource, base string) {
+	limit := queryInt(r, "limit", 20)
+	offset := queryInt(r, "offset", 0)
+
+	names := s.sortedByID(resource)
+	total := len(names)
+
+	type item struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+	results := []item{}
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, item{
+			Name: names[i].name,
+			URL:  fmt.Sprintf("%s/%s/%d/", base, resource, names[i].id),
+		})
+	}
+
+	var next, previous *string
+	if offset+limit < total {
+		u := fmt.Sprintf("%s/%s?offset=%d&limit=%d", base, resource, offset+limit, limit)
+		next = &u
+	}
+	if offset > 0 {
+		u := fmt.Sprintf("%s/%s?offset=%d&limit=%d", base, resource, max(offset-limit, 0), limit)
+		previous = &u
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(map[string]interface{}{
+		"count":    total,
+		"next":     next,
+		"previous": previous,
+		"results":  results,
+	})
+}
+
+type namedID struct {
+	name string
+	id   int
+}
+
+func (s *Server) sortedByID(resource string) []namedID {
+	var out []namedID
+	for id, name := range s.ids[resource] {
+		n, _ := strconv.Atoi(id)
+		out = append(out, namedID{name: name, id: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out
+}
+
+func queryInt(r *http.Request, key string, def int) int {
+	n, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil || n < 0 {
+		return def
+	}
+	return n
+}
+
+func writeError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(http.StatusText(status)))
+}
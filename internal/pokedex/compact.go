@@ -0,0 +1,94 @@
+package pokedex
+
+// keepFields lists the top-level fields kept for each kind. Everything else
+// (game indices, held items, encounter URLs, cries...) is dropped.
+var keepFields = map[string][]string{
+	Pokemon: {
+		"id", "name", "height", "weight", "base_experience", "is_default",
+		"types", "past_types", "abilities", "stats", "sprites", "species", "moves",
+	},
+	Species: {
+		"id", "name", "names", "genera", "flavor_text_entries", "generation",
+		"is_legendary", "is_mythical", "is_baby", "capture_rate",
+		"evolution_chain", "evolves_from_species", "varieties",
+	},
+	Type: {
+		"id", "name", "names", "damage_relations", "generation", "pokemon",
+	},
+	Ability: {
+		"id", "name", "names", "effect_entries", "flavor_text_entries",
+		"generation", "pokemon",
+	},
+	Move: {
+		"id", "name", "names", "accuracy", "power", "pp", "priority",
+		"effect_chance", "type", "damage_class", "effect_entries",
+		"flavor_text_entries", "generation", "target",
+	},
+	EvolutionChain: {
+		"id", "chain", "baby_trigger_item",
+	},
+}
+
+// compact trims a PokeAPI document to the fields the service uses
+func compact(kind string, doc map[string]interface{}) map[string]interface{} {
+	fields, ok := keepFields[kind]
+	if !ok {
+		return doc
+	}
+
+	out := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := doc[field]; ok {
+			out[field] = value
+		}
+	}
+
+	if sprites, ok := out["sprites"].(map[string]interface{}); ok {
+		out["sprites"] = compactSprites(sprites)
+	}
+	if entries, ok := out["flavor_text_entries"].([]interface{}); ok {
+		out["flavor_text_entries"] = latestPerLanguage(entries)
+	}
+	return out
+}
+
+// compactSprites keeps the default sprite and the official artwork
+func compactSprites(sprites map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{
+		"front_default": sprites["front_default"],
+	}
+	if other, ok := sprites["other"].(map[string]interface{}); ok {
+		if artwork, ok := other["official-artwork"].(map[string]interface{}); ok {
+			out["other"] = map[string]interface{}{
+				"official-artwork": map[string]interface{}{
+					"front_default": artwork["front_default"],
+				},
+			}
+		}
+	}
+	return out
+}
+
+// latestPerLanguage keeps the last flavor text of each language. PokeAPI
+// lists one entry per game version, oldest first.
+func latestPerLanguage(entries []interface{}) []interface{} {
+	index := make(map[string]int)
+	var out []interface{}
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		lang := ""
+		if language, ok := entry["language"].(map[string]interface{}); ok {
+			lang, _ = language["name"].(string)
+		}
+		if i, seen := index[lang]; seen {
+			out[i] = entry
+			continue
+		}
+		index[lang] = len(out)
+		out = append(out, entry)
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package pokedex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Import builds a snapshot from a PokeAPI dump such as the PokeAPI/api-data
+// repository, pointed at its api/v2 directory. Each kind is read from
+// <dir>/<kind>/<id>/index.json; flat <dir>/<kind>/<id>.json files are
+// accepted too. Missing kinds are skipped. progress, when not nil, is called
+// after each kind with the number of entries imported.
+func Import(dir string, progress func(kind string, count int)) (*Store, error) {
+	store := New()
+
+	for _, kind := range Kinds {
+		kindDir := filepath.Join(dir, kind)
+		entries, err := os.ReadDir(kindDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			var path string
+			switch {
+			case entry.IsDir():
+				path = filepath.Join(kindDir, entry.Name(), "index.json")
+			case strings.HasSuffix(entry.Name(), ".json") && entry.Name() != "index.json":
+				// The top-level index.json is the paginated list, not a resource
+				path = filepath.Join(kindDir, entry.Name())
+			default:
+				continue
+			}
+
+			raw, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := store.Put(kind, raw); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+
+		if progress != nil {
+			progress(kind, store.Count(kind))
+		}
+	}
+
+	if store.Count(Pokemon) == 0 {
+		return nil, fmt.Errorf("no pokemon found under %s, expected a PokeAPI api/v2 dump", dir)
+	}
+	return store, nil
+}
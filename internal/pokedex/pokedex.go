@@ -0,0 +1,170 @@
+// Package pokedex is a compact local copy of the PokeAPI resources the
+// service uses, so lookups keep working when pokeapi.co is unreachable.
+package pokedex
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resources held in a snapshot, named after their PokeAPI endpoints
+const (
+	Pokemon        = "pokemon"
+	Species        = "pokemon-species"
+	Type           = "type"
+	Ability        = "ability"
+	Move           = "move"
+	EvolutionChain = "evolution-chain"
+)
+
+// Kinds lists every resource a snapshot can hold
+var Kinds = []string{Pokemon, Species, Type, Ability, Move, EvolutionChain}
+
+// formatVersion is bumped whenever the snapshot layout changes
+const formatVersion = 1
+
+// Store is an in-memory Pokedex snapshot. Entries are PokeAPI JSON documents
+// trimmed to the fields the service reads, keyed by name with an id index.
+type Store struct {
+	Version   int                                   `json:"version"`
+	BuiltAt   time.Time                             `json:"built_at"`
+	Resources map[string]map[string]json.RawMessage `json:"resources"`
+	IDs       map[string]map[string]string          `json:"ids"`
+}
+
+// New returns an empty store
+func New() *Store {
+	return &Store{
+		Version:   formatVersion,
+		BuiltAt:   time.Now().UTC(),
+		Resources: make(map[string]map[string]json.RawMessage),
+		IDs:       make(map[string]map[string]string),
+	}
+}
+
+// Load reads a gzip-compressed snapshot written by Save
+func Load(path string) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pokedex %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	var s Store
+	if err := json.NewDecoder(zr).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse pokedex %s: %w", path, err)
+	}
+	if s.Version != formatVersion {
+		return nil, fmt.Errorf("pokedex %s has format version %d, expected %d", path, s.Version, formatVersion)
+	}
+	if s.Resources == nil {
+		s.Resources = make(map[string]map[string]json.RawMessage)
+	}
+	if s.IDs == nil {
+		s.IDs = make(map[string]map[string]string)
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot gzip-compressed to path
+func (s *Store) Save(path string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Get returns the JSON of a resource by name or numeric id
+func (s *Store) Get(kind, nameOrID string) ([]byte, bool) {
+	entries, ok := s.Resources[kind]
+	if !ok {
+		return nil, false
+	}
+	key := strings.ToLower(nameOrID)
+	if name, ok := s.IDs[kind][key]; ok {
+		key = name
+	}
+	raw, ok := entries[key]
+	return raw, ok
+}
+
+// Put compacts a PokeAPI document and adds it to the store
+func (s *Store) Put(kind string, raw []byte) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("invalid %s document: %w", kind, err)
+	}
+
+	compacted, err := json.Marshal(compact(kind, doc))
+	if err != nil {
+		return err
+	}
+
+	id := ""
+	if n, ok := doc["id"].(float64); ok {
+		id = strconv.Itoa(int(n))
+	}
+	name, _ := doc["name"].(string)
+	if name == "" {
+		// Evolution chains have no name, only an id
+		name = id
+	}
+	if name == "" {
+		return fmt.Errorf("%s document has neither name nor id", kind)
+	}
+
+	if s.Resources[kind] == nil {
+		s.Resources[kind] = make(map[string]json.RawMessage)
+		s.IDs[kind] = make(map[string]string)
+	}
+	s.Resources[kind][name] = compacted
+	if id != "" && id != name {
+		s.IDs[kind][id] = name
+	}
+	return nil
+}
+
+// Names returns the sorted names of every entry of a kind
+func (s *Store) Names(kind string) []string {
+	names := make([]string, 0, len(s.Resources[kind]))
+	for name := range s.Resources[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Count returns the number of entries of a kind
+func (s *Store) Count(kind string) int {
+	return len(s.Resources[kind])
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last row of a keyset page. Clients only ever see it
+// encoded, so its fields can change without breaking the API.
+type Cursor struct {
+	SortBy string `json:"s"`
+	Asc    bool   `json:"a,omitempty"`
+	Value  string `json:"v,omitempty"`
+	Null   bool   `json:"n,omitempty"`
+	ID     int    `json:"i"`
+}
+
+// EncodeCursor returns the opaque, URL-safe form of a cursor
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.SortBy == "" || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// after restricts q to rows following the cursor when ordered by the
+// cursor's sort column (nulls last) with id as tie-breaker. Once the cursor
+// reaches a null value only the remaining nulls follow.
+func (c Cursor) after(q *Query) *Query {
+	op := "lt"
+	if c.Asc {
+		op = "gt"
+	}
+
+	if c.Null {
+		return q.Is(c.SortBy, "null").Filter("id", op, c.ID)
+	}
+
+	value := quoteFilterValue(c.Value)
+	return q.Or(fmt.Sprintf("(%s.%s.%s,%s.is.null,and(%s.eq.%s,id.%s.%d))",
+		c.SortBy, op, value, c.SortBy, c.SortBy, value, op, c.ID))
+}
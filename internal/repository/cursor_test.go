@@ -0,0 +1,97 @@
+package repository_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursors := []repository.Cursor{
+		{SortBy: "registered_at", Value: "2024-03-01T10:00:00Z", ID: 7},
+		{SortBy: "first_name", Asc: true, Value: `Ash, "Jr." (Pallet)`, ID: 1},
+		{SortBy: "last_active", Null: true, ID: 42},
+	}
+	for _, c := range cursors {
+		encoded := repository.EncodeCursor(c)
+		if url.QueryEscape(encoded) != encoded {
+			t.Errorf("EncodeCursor(%+v) = %q, not URL-safe", c, encoded)
+		}
+		decoded, err := repository.DecodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q): %v", encoded, err)
+		}
+		if *decoded != c {
+			t.Errorf("round trip = %+v, want %+v", *decoded, c)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("not json")),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"i":1}`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"s":"first_name","i":0}`)),
+	}
+	for _, s := range invalid {
+		if _, err := repository.DecodeCursor(s); !errors.Is(err, repository.ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
+
+// TestCursorFilter checks the keyset filter sent to PostgREST: rows after
+// the cursor value, then the nulls (sorted last), then ties by id
+func TestCursorFilter(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte("[]"))
+	}))
+	t.Cleanup(srv.Close)
+	users := repository.NewUserRepository(srv.URL, testKey)
+
+	tests := []struct {
+		name   string
+		cursor repository.Cursor
+		want   url.Values
+	}{
+		{
+			"descending",
+			repository.Cursor{SortBy: "registered_at", Value: "2024-03-01T10:00:00Z", ID: 7},
+			url.Values{"or": {`(registered_at.lt."2024-03-01T10:00:00Z",registered_at.is.null,and(registered_at.eq."2024-03-01T10:00:00Z",id.lt.7))`}},
+		},
+		{
+			"ascending, quoted",
+			repository.Cursor{SortBy: "first_name", Asc: true, Value: `Ash, "Jr."`, ID: 1},
+			url.Values{"or": {`(first_name.gt."Ash, \"Jr.\"",first_name.is.null,and(first_name.eq."Ash, \"Jr.\"",id.gt.1))`}},
+		},
+		{
+			// Past the last value only nulls are left
+			"null",
+			repository.Cursor{SortBy: "last_active", Null: true, ID: 42},
+			url.Values{"last_active": {"is.null"}, "id": {"lt.42"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := tt.cursor
+			if _, _, err := users.FindAllAfter(repository.UserListOptions{}, &cursor, 2); err != nil {
+				t.Fatalf("FindAllAfter: %v", err)
+			}
+			for key, want := range tt.want {
+				if got := query[key]; len(got) != 1 || got[0] != want[0] {
+					t.Errorf("%s = %q, want %q", key, got, want[0])
+				}
+			}
+			if _, ok := tt.want["or"]; !ok && query.Has("or") {
+				t.Errorf("null cursor sent or=%s", query.Get("or"))
+			}
+		})
+	}
+}
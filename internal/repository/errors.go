@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Domain errors returned by the repositories
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+
+	ErrFavoriteNotFound = errors.New("favorite not found")
+	ErrFavoriteExists   = errors.New("favorite already exists")
+
+	ErrTeamNotFound = errors.New("team not found")
+	ErrTeamExists   = errors.New("team already exists")
+)
+
+// PostgreSQL and PostgREST error codes the repositories act on
+const (
+	codeUniqueViolation = "23505"
+	codeNoRows          = "PGRST116" // Single() matched zero (or many) rows
+)
+
+// APIError is a non-2xx response from Supabase/PostgREST
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	Hint    string `json:"hint"`
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error (status %d)", e.Status)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Details != "" {
+		msg += " (" + e.Details + ")"
+	}
+	return msg
+}
+
+// parseAPIError builds an APIError from a failed response. Bodies that are
+// not PostgREST JSON (proxies, gateways) end up in Message.
+func parseAPIError(status int, body []byte) *APIError {
+	apiErr := &APIError{Status: status}
+	if err := json.Unmarshal(body, apiErr); err != nil || (apiErr.Code == "" && apiErr.Message == "") {
+		apiErr.Message = string(body)
+	}
+	return apiErr
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == codeUniqueViolation
+}
+
+// IsNotFound reports whether err means a single-row request matched no
+// row. Other 404s, such as a missing table, are not.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == codeNoRows
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"fmt"
+)
+
+// Favorite is a Pokemon a user saved, stored under its PokeAPI name
+type Favorite struct {
+	ID          int    `json:"id,omitempty"`
+	TelegramID  string `json:"telegram_id"`
+	PokemonName string `json:"pokemon_name"`
+	PokemonID   *int   `json:"pokemon_id,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+type FavoriteRepository interface {
+	Add(favorite *Favorite) error
+	Remove(telegramID, pokemonName string) error
+	ListByUser(telegramID string) ([]Favorite, error)
+	CountByPokemon(window TimeWindow) (map[string]int, error)
+}
+
+type favoriteRepository struct {
+	client *SupabaseClient
+}
+
+func NewFavoriteRepository(supabaseURL, supabaseKey string) FavoriteRepository {
+	return &favoriteRepository{
+		client: NewSupabaseClient(supabaseURL, supabaseKey),
+	}
+}
+
+// Add saves a favorite, filling in its id and creation time. Saving the
+// same Pokemon twice for a user is ErrFavoriteExists.
+func (r *favoriteRepository) Add(favorite *Favorite) error {
+	var results []Favorite
+	if _, err := r.client.From("favorites").Insert(favorite).ExecuteInto(&results); err != nil {
+		if IsUniqueViolation(err) {
+			return ErrFavoriteExists
+		}
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+
+	if len(results) > 0 {
+		*favorite = results[0]
+	}
+	return nil
+}
+
+func (r *favoriteRepository) Remove(telegramID, pokemonName string) error {
+	var deleted []Favorite
+	_, err := r.client.From("favorites").
+		Eq("telegram_id", telegramID).
+		Eq("pokemon_name", pokemonName).
+		Delete().
+		ExecuteInto(&deleted)
+	if err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+
+	if len(deleted) == 0 {
+		return ErrFavoriteNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's favorites in the order they were added
+func (r *favoriteRepository) ListByUser(telegramID string) ([]Favorite, error) {
+	var favorites []Favorite
+	_, err := r.client.From("favorites").
+		Eq("telegram_id", telegramID).
+		Order("created_at", true).
+		Order("id", true).
+		ExecuteInto(&favorites)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+	return favorites, nil
+}
+
+// CountByPokemon counts the favorites added in window per Pokemon
+func (r *favoriteRepository) CountByPokemon(window TimeWindow) (map[string]int, error) {
+	var favorites []Favorite
+	_, err := window.apply(r.client.From("favorites"), "created_at").
+		Select("pokemon_name").
+		ExecuteInto(&favorites)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, f := range favorites {
+		counts[f.PokemonName]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+func TestFavoriteAddAndRemove(t *testing.T) {
+	url, _ := startSupabase(t)
+	favorites := repository.NewFavoriteRepository(url, testKey)
+
+	id := 25
+	pikachu := &repository.Favorite{TelegramID: "1", PokemonName: "pikachu", PokemonID: &id}
+	if err := favorites.Add(pikachu); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if pikachu.ID == 0 || pikachu.CreatedAt == "" {
+		t.Errorf("Add didn't fill in id and created_at: %+v", pikachu)
+	}
+
+	// 23505 from the unique (telegram_id, pokemon_name)
+	err := favorites.Add(&repository.Favorite{TelegramID: "1", PokemonName: "pikachu"})
+	if !errors.Is(err, repository.ErrFavoriteExists) {
+		t.Errorf("Add duplicate = %v, want ErrFavoriteExists", err)
+	}
+	// Other users may favorite the same Pokemon
+	if err := favorites.Add(&repository.Favorite{TelegramID: "2", PokemonName: "pikachu"}); err != nil {
+		t.Errorf("Add for another user: %v", err)
+	}
+
+	if err := favorites.Remove("1", "pikachu"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := favorites.Remove("1", "pikachu"); !errors.Is(err, repository.ErrFavoriteNotFound) {
+		t.Errorf("Remove twice = %v, want ErrFavoriteNotFound", err)
+	}
+}
+
+func TestFavoriteListByUser(t *testing.T) {
+	url, fake := startSupabase(t)
+	seed(t, fake, "favorites",
+		map[string]interface{}{"telegram_id": "1", "pokemon_name": "mew", "created_at": "2024-03-02T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "1", "pokemon_name": "pikachu", "created_at": "2024-03-01T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "2", "pokemon_name": "eevee", "created_at": "2024-03-01T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "1", "pokemon_name": "eevee", "created_at": "2024-03-02T00:00:00Z"},
+	)
+	favorites := repository.NewFavoriteRepository(url, testKey)
+
+	list, err := favorites.ListByUser("1")
+	if err != nil {
+		t.Fatalf("ListByUser: %v", err)
+	}
+	// Oldest first, id breaking ties
+	want := []string{"pikachu", "mew", "eevee"}
+	if len(list) != len(want) {
+		t.Fatalf("ListByUser = %+v, want %v", list, want)
+	}
+	for i, f := range list {
+		if f.PokemonName != want[i] || f.TelegramID != "1" {
+			t.Errorf("favorite %d = %s of %s, want %s of 1", i, f.PokemonName, f.TelegramID, want[i])
+		}
+	}
+
+	none, err := favorites.ListByUser("3")
+	if err != nil || len(none) != 0 {
+		t.Errorf("ListByUser without favorites = %v, %v", none, err)
+	}
+}
+
+func TestFavoriteCountByPokemon(t *testing.T) {
+	url, fake := startSupabase(t)
+	seed(t, fake, "favorites",
+		map[string]interface{}{"telegram_id": "1", "pokemon_name": "pikachu", "created_at": "2024-03-01T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "2", "pokemon_name": "pikachu", "created_at": "2024-03-03T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "3", "pokemon_name": "pikachu", "created_at": "2024-03-05T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "1", "pokemon_name": "mew", "created_at": "2024-03-03T00:00:00Z"},
+	)
+	favorites := repository.NewFavoriteRepository(url, testKey)
+
+	counts, err := favorites.CountByPokemon(repository.TimeWindow{})
+	if err != nil {
+		t.Fatalf("CountByPokemon: %v", err)
+	}
+	if counts["pikachu"] != 3 || counts["mew"] != 1 {
+		t.Errorf("counts = %v, want pikachu 3 and mew 1", counts)
+	}
+
+	from, to := at("2024-03-02T00:00:00Z"), at("2024-03-05T00:00:00Z")
+	counts, err = favorites.CountByPokemon(repository.TimeWindow{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("CountByPokemon: %v", err)
+	}
+	if counts["pikachu"] != 1 || counts["mew"] != 1 {
+		t.Errorf("windowed counts = %v, want pikachu 1 and mew 1", counts)
+	}
+
+	// Merged into search stats, names match case-insensitively
+	stats := &repository.SearchStats{TopSearched: []repository.TopSearchedItem{{PokemonName: "Mew", Count: 4}}}
+	stats.AddFavorites(counts)
+	if stats.TotalFavorites != 2 || stats.TopSearched[0].Favorites != 1 {
+		t.Errorf("stats = %+v, want 2 favorites and Mew with 1", stats)
+	}
+}
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CountMode selects how PostgREST counts matching rows
+type CountMode string
+
+const (
+	CountNone      CountMode = ""
+	CountExact     CountMode = "exact"
+	CountPlanned   CountMode = "planned"
+	CountEstimated CountMode = "estimated"
+)
+
+// Query is a fluent PostgREST request builder. Filters are added as
+// separate URL params, so values never need manual escaping:
+//
+//	client.From("users").Select("id", "first_name").
+//		ILike("first_name", "*ash*").
+//		Order("registered_at", false).
+//		Limit(10).
+//		Execute()
+type Query struct {
+	client   *SupabaseClient
+	table    string
+	method   string
+	params   url.Values
+	orGroups []string
+	orders   []string
+	body     interface{}
+	prefer   []string
+	count    CountMode
+	single   bool
+	rangeSet bool
+	from, to int
+}
+
+// Select limits the returned columns (all columns when never called)
+func (q *Query) Select(columns ...string) *Query {
+	q.params.Set("select", strings.Join(columns, ","))
+	return q
+}
+
+// Eq matches rows where column equals value
+func (q *Query) Eq(column string, value interface{}) *Query {
+	return q.Filter(column, "eq", value)
+}
+
+// Neq matches rows where column is not equal to value
+func (q *Query) Neq(column string, value interface{}) *Query {
+	return q.Filter(column, "neq", value)
+}
+
+// Gt matches rows where column is greater than value
+func (q *Query) Gt(column string, value interface{}) *Query {
+	return q.Filter(column, "gt", value)
+}
+
+// Gte matches rows where column is greater than or equal to value
+func (q *Query) Gte(column string, value interface{}) *Query {
+	return q.Filter(column, "gte", value)
+}
+
+// Lt matches rows where column is less than value
+func (q *Query) Lt(column string, value interface{}) *Query {
+	return q.Filter(column, "lt", value)
+}
+
+// Lte matches rows where column is less than or equal to value
+func (q *Query) Lte(column string, value interface{}) *Query {
+	return q.Filter(column, "lte", value)
+}
+
+// Like matches column against a case-sensitive pattern using * as wildcard
+func (q *Query) Like(column, pattern string) *Query {
+	return q.Filter(column, "like", pattern)
+}
+
+// ILike matches column against a case-insensitive pattern using * as wildcard
+func (q *Query) ILike(column, pattern string) *Query {
+	return q.Filter(column, "ilike", pattern)
+}
+
+// In matches rows where column is one of values
+func (q *Query) In(column string, values ...interface{}) *Query {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quoteFilterValue(formatFilterValue(v))
+	}
+	q.params.Add(column, "in.("+strings.Join(quoted, ",")+")")
+	return q
+}
+
+// Is matches rows where column IS null, true or false
+func (q *Query) Is(column, value string) *Query {
+	q.params.Add(column, "is."+value)
+	return q
+}
+
+// Filter adds a column.operator.value filter for operators without a
+// dedicated helper. Filters on the same column are ANDed.
+func (q *Query) Filter(column, operator string, value interface{}) *Query {
+	q.params.Add(column, operator+"."+formatFilterValue(value))
+	return q
+}
+
+// Or adds a group of PostgREST conditions of which at least one must match,
+// e.g. "(first_name.ilike.*ash*,username.ilike.*ash*)". Values inside the
+// group must be quoted with quoteFilterValue. Several groups are ANDed.
+func (q *Query) Or(conditions string) *Query {
+	q.orGroups = append(q.orGroups, conditions)
+	return q
+}
+
+// Order appends a sort column. Later calls break ties of earlier ones.
+func (q *Query) Order(column string, ascending bool) *Query {
+	q.orders = append(q.orders, column+"."+direction(ascending))
+	return q
+}
+
+// OrderNullsLast appends a sort column that places nulls after all values
+// regardless of direction
+func (q *Query) OrderNullsLast(column string, ascending bool) *Query {
+	q.orders = append(q.orders, column+"."+direction(ascending)+".nullslast")
+	return q
+}
+
+// Limit caps the number of returned rows
+func (q *Query) Limit(limit int) *Query {
+	q.params.Set("limit", strconv.Itoa(limit))
+	return q
+}
+
+// Offset skips the first rows of the result
+func (q *Query) Offset(offset int) *Query {
+	q.params.Set("offset", strconv.Itoa(offset))
+	return q
+}
+
+// Range requests rows from..to (inclusive, zero-based) via the Range header
+func (q *Query) Range(from, to int) *Query {
+	q.rangeSet = true
+	q.from, q.to = from, to
+	return q
+}
+
+// Count asks PostgREST to report the number of matching rows, available
+// as Result.Total
+func (q *Query) Count(mode CountMode) *Query {
+	q.count = mode
+	return q
+}
+
+// Single expects exactly one row and returns it as an object instead of
+// an array. PostgREST fails the request otherwise.
+func (q *Query) Single() *Query {
+	q.single = true
+	return q
+}
+
+// Insert turns the query into a POST of data (a struct, map or slice)
+func (q *Query) Insert(data interface{}) *Query {
+	q.method = http.MethodPost
+	q.body = data
+	return q
+}
+
+// Upsert inserts data, merging into existing rows that conflict on the
+// given columns (the primary key when none are given)
+func (q *Query) Upsert(data interface{}, onConflict ...string) *Query {
+	q.method = http.MethodPost
+	q.body = data
+	q.prefer = append(q.prefer, "resolution=merge-duplicates")
+	if len(onConflict) > 0 {
+		q.params.Set("on_conflict", strings.Join(onConflict, ","))
+	}
+	return q
+}
+
+// Update turns the query into a PATCH of the filtered rows
+func (q *Query) Update(data interface{}) *Query {
+	q.method = http.MethodPatch
+	q.body = data
+	return q
+}
+
+// Delete turns the query into a DELETE of the filtered rows
+func (q *Query) Delete() *Query {
+	q.method = http.MethodDelete
+	return q
+}
+
+// Head turns the query into a HEAD request, useful with Count to get a
+// total without transferring rows
+func (q *Query) Head() *Query {
+	q.method = http.MethodHead
+	return q
+}
+
+// Execute sends the query. Writes return the affected rows.
+func (q *Query) Execute() (*Result, error) {
+	params := url.Values{}
+	for key, values := range q.params {
+		params[key] = append([]string(nil), values...)
+	}
+	if q.method == http.MethodGet && params.Get("select") == "" {
+		params.Set("select", "*")
+	}
+	switch len(q.orGroups) {
+	case 0:
+	case 1:
+		params.Set("or", q.orGroups[0])
+	default:
+		// PostgREST accepts a single or= param, so AND the groups together
+		groups := make([]string, len(q.orGroups))
+		for i, g := range q.orGroups {
+			groups[i] = "or" + g
+		}
+		params.Set("and", "("+strings.Join(groups, ",")+")")
+	}
+	if len(q.orders) > 0 && q.method != http.MethodHead {
+		params.Set("order", strings.Join(q.orders, ","))
+	}
+
+	header := http.Header{}
+	prefer := append([]string(nil), q.prefer...)
+	switch q.method {
+	case http.MethodPost, http.MethodPatch, http.MethodDelete:
+		prefer = append(prefer, "return=representation")
+	}
+	if q.count != CountNone {
+		prefer = append(prefer, "count="+string(q.count))
+	}
+	if len(prefer) > 0 {
+		header.Set("Prefer", strings.Join(prefer, ","))
+	}
+	if q.single {
+		header.Set("Accept", "application/vnd.pgrst.object+json")
+	}
+	if q.rangeSet {
+		header.Set("Range-Unit", "items")
+		header.Set("Range", fmt.Sprintf("%d-%d", q.from, q.to))
+	}
+
+	var body []byte
+	if q.body != nil {
+		data, err := json.Marshal(q.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+		body = data
+	}
+
+	return q.client.do(q.method, q.table, params, header, body)
+}
+
+// ExecuteInto sends the query and decodes the response body into dest
+func (q *Query) ExecuteInto(dest interface{}) (*Result, error) {
+	result, err := q.Execute()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(result.Body, dest); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result, nil
+}
+
+func direction(ascending bool) string {
+	if ascending {
+		return "asc"
+	}
+	return "desc"
+}
+
+// formatFilterValue renders a filter operand the way PostgREST parses it
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return "null"
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
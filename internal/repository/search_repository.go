@@ -1,8 +1,10 @@
 package repository
 
 import (
-	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
 type PokemonSearch struct {
@@ -14,21 +16,75 @@ type PokemonSearch struct {
 }
 
 type SearchStats struct {
-	TotalSearches   int                `json:"total_searches"`
-	FoundSearches   int                `json:"found_searches"`
+	TotalSearches    int               `json:"total_searches"`
+	FoundSearches    int               `json:"found_searches"`
 	NotFoundSearches int               `json:"not_found_searches"`
-	TopSearched     []TopSearchedItem  `json:"top_searched"`
-	RecentSearches  []PokemonSearch    `json:"recent_searches"`
+	TopSearched      []TopSearchedItem `json:"top_searched"`
+	RecentSearches   []PokemonSearch   `json:"recent_searches"`
+	// Favorites added in the same window, when favorites are stored
+	TotalFavorites int                `json:"total_favorites"`
+	TopFavorited   []TopFavoritedItem `json:"top_favorited,omitempty"`
 }
 
 type TopSearchedItem struct {
 	PokemonName string `json:"pokemon_name"`
 	Count       int    `json:"count"`
+	// Favorites is how many users favorited the Pokemon in the window
+	Favorites int `json:"favorites"`
+}
+
+type TopFavoritedItem struct {
+	PokemonName string `json:"pokemon_name"`
+	Count       int    `json:"count"`
+}
+
+// AddFavorites merges favorite counts per Pokemon into the stats: the
+// total, the ten most favorited and the favorites of each top searched
+// Pokemon. Searches are logged under display names ("Pikachu"), so those
+// are matched ignoring case.
+func (s *SearchStats) AddFavorites(counts map[string]int) {
+	s.TopFavorited = []TopFavoritedItem{}
+	for name, count := range counts {
+		s.TotalFavorites += count
+		s.TopFavorited = append(s.TopFavorited, TopFavoritedItem{PokemonName: name, Count: count})
+	}
+	sort.Slice(s.TopFavorited, func(i, j int) bool {
+		a, b := s.TopFavorited[i], s.TopFavorited[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.PokemonName < b.PokemonName
+	})
+	if len(s.TopFavorited) > 10 {
+		s.TopFavorited = s.TopFavorited[:10]
+	}
+
+	for i := range s.TopSearched {
+		s.TopSearched[i].Favorites = counts[strings.ToLower(s.TopSearched[i].PokemonName)]
+	}
+}
+
+// TimeWindow bounds a query by time. A nil end leaves that side open.
+type TimeWindow struct {
+	From *time.Time
+	To   *time.Time
+}
+
+func (w TimeWindow) apply(q *Query, column string) *Query {
+	if w.From != nil {
+		q.Gte(column, *w.From)
+	}
+	if w.To != nil {
+		q.Lt(column, *w.To)
+	}
+	return q
 }
 
 type SearchRepository interface {
 	LogSearch(pokemonName string, pokemonID *int, found bool) error
-	GetStats() (*SearchStats, error)
+	GetStats(window TimeWindow) (*SearchStats, error)
+	ListSearches(after *Cursor, limit int) ([]PokemonSearch, *Cursor, error)
+	DeleteBefore(before time.Time) (int, error)
 }
 
 type searchRepository struct {
@@ -48,22 +104,20 @@ func (r *searchRepository) LogSearch(pokemonName string, pokemonID *int, found b
 		Found:       found,
 	}
 
-	_, err := r.client.Insert("pokemon_searches", search)
+	_, err := r.client.From("pokemon_searches").Insert(search).Execute()
 	return err
 }
 
-func (r *searchRepository) GetStats() (*SearchStats, error) {
+func (r *searchRepository) GetStats(window TimeWindow) (*SearchStats, error) {
 	// Get total counts (ordered by searched_at)
-	allBody, err := r.client.SelectAllOrdered("pokemon_searches", "searched_at.desc")
+	var allSearches []PokemonSearch
+	_, err := window.apply(r.client.From("pokemon_searches"), "searched_at").
+		Order("searched_at", false).
+		ExecuteInto(&allSearches)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get searches: %w", err)
 	}
 
-	var allSearches []PokemonSearch
-	if err := json.Unmarshal(allBody, &allSearches); err != nil {
-		return nil, fmt.Errorf("failed to parse searches: %w", err)
-	}
-
 	// Calculate stats
 	stats := &SearchStats{
 		TotalSearches: len(allSearches),
@@ -119,3 +173,53 @@ func (r *searchRepository) GetStats() (*SearchStats, error) {
 
 	return stats, nil
 }
+
+// ListSearches returns up to limit logged searches, newest first, following
+// the after cursor (or from the start when nil). The returned cursor is nil
+// on the last page.
+func (r *searchRepository) ListSearches(after *Cursor, limit int) ([]PokemonSearch, *Cursor, error) {
+	q := r.client.From("pokemon_searches")
+	if after != nil {
+		if after.SortBy != "searched_at" || after.Asc {
+			return nil, nil, ErrInvalidCursor
+		}
+		after.after(q)
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	var searches []PokemonSearch
+	_, err := q.OrderNullsLast("searched_at", false).
+		Order("id", false).
+		Limit(limit + 1).
+		ExecuteInto(&searches)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get searches: %w", err)
+	}
+
+	if len(searches) <= limit {
+		return searches, nil, nil
+	}
+	searches = searches[:limit]
+	last := searches[limit-1]
+	return searches, &Cursor{
+		SortBy: "searched_at",
+		Value:  last.SearchedAt,
+		Null:   last.SearchedAt == "",
+		ID:     last.ID,
+	}, nil
+}
+
+// DeleteBefore purges searches logged before the given time and returns how
+// many were removed
+func (r *searchRepository) DeleteBefore(before time.Time) (int, error) {
+	var deleted []PokemonSearch
+	_, err := r.client.From("pokemon_searches").
+		Lt("searched_at", before).
+		Select("id").
+		Delete().
+		ExecuteInto(&deleted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete searches: %w", err)
+	}
+	return len(deleted), nil
+}
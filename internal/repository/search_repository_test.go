@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/supabasefake"
+)
+
+// seedSearches logs six searches an hour apart; the last two share a time
+func seedSearches(t *testing.T, fake *supabasefake.Server) {
+	t.Helper()
+	seed(t, fake, "pokemon_searches",
+		map[string]interface{}{"pokemon_name": "Pikachu", "pokemon_id": 25, "found": true, "searched_at": "2024-03-01T10:00:00Z"},
+		map[string]interface{}{"pokemon_name": "missingno", "found": false, "searched_at": "2024-03-01T11:00:00Z"},
+		map[string]interface{}{"pokemon_name": "Pikachu", "pokemon_id": 25, "found": true, "searched_at": "2024-03-01T12:00:00Z"},
+		map[string]interface{}{"pokemon_name": "Mew", "pokemon_id": 151, "found": true, "searched_at": "2024-03-01T13:00:00Z"},
+		map[string]interface{}{"pokemon_name": "Pikachu", "pokemon_id": 25, "found": true, "searched_at": "2024-03-01T14:00:00Z"},
+		map[string]interface{}{"pokemon_name": "Mew", "pokemon_id": 151, "found": true, "searched_at": "2024-03-01T14:00:00Z"},
+	)
+}
+
+func TestSearchLogAndStats(t *testing.T) {
+	url, _ := startSupabase(t)
+	searches := repository.NewSearchRepository(url, testKey)
+
+	id := 25
+	if err := searches.LogSearch("Pikachu", &id, true); err != nil {
+		t.Fatalf("LogSearch: %v", err)
+	}
+	if err := searches.LogSearch("missingno", nil, false); err != nil {
+		t.Fatalf("LogSearch: %v", err)
+	}
+
+	stats, err := searches.GetStats(repository.TimeWindow{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalSearches != 2 || stats.FoundSearches != 1 || stats.NotFoundSearches != 1 {
+		t.Errorf("stats = %d total, %d found, %d not found; want 2, 1, 1",
+			stats.TotalSearches, stats.FoundSearches, stats.NotFoundSearches)
+	}
+}
+
+func TestSearchStatsWindow(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedSearches(t, fake)
+	searches := repository.NewSearchRepository(url, testKey)
+
+	stats, err := searches.GetStats(repository.TimeWindow{})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalSearches != 6 || stats.NotFoundSearches != 1 {
+		t.Errorf("stats = %d total, %d not found; want 6, 1", stats.TotalSearches, stats.NotFoundSearches)
+	}
+	if len(stats.TopSearched) != 3 || stats.TopSearched[0].PokemonName != "Pikachu" || stats.TopSearched[0].Count != 3 {
+		t.Errorf("top searched = %+v, want Pikachu first with 3", stats.TopSearched)
+	}
+
+	// The window includes its start and excludes its end
+	from, to := at("2024-03-01T11:00:00Z"), at("2024-03-01T14:00:00Z")
+	stats, err = searches.GetStats(repository.TimeWindow{From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalSearches != 3 {
+		t.Errorf("windowed total = %d, want 3", stats.TotalSearches)
+	}
+	if stats.RecentSearches[0].PokemonName != "Mew" {
+		t.Errorf("most recent = %s, want Mew", stats.RecentSearches[0].PokemonName)
+	}
+}
+
+func TestSearchListPaging(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedSearches(t, fake)
+	searches := repository.NewSearchRepository(url, testKey)
+
+	// Newest first, with id breaking the 14:00 tie
+	want := []int{6, 5, 4, 3, 2, 1}
+	var got []int
+	var after *repository.Cursor
+	for pages := 0; ; pages++ {
+		if pages > len(want) {
+			t.Fatalf("paging didn't end after %d pages", pages)
+		}
+		page, next, err := searches.ListSearches(after, 4)
+		if err != nil {
+			t.Fatalf("ListSearches: %v", err)
+		}
+		for _, s := range page {
+			got = append(got, s.ID)
+		}
+		if next == nil {
+			break
+		}
+		after = next
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ids = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ids = %v, want %v", got, want)
+		}
+	}
+
+	bad := &repository.Cursor{SortBy: "searched_at", Asc: true, ID: 1}
+	if _, _, err := searches.ListSearches(bad, 4); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("ListSearches with an ascending cursor = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestSearchDeleteBefore(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedSearches(t, fake)
+	searches := repository.NewSearchRepository(url, testKey)
+
+	deleted, err := searches.DeleteBefore(at("2024-03-01T12:00:00Z"))
+	if err != nil {
+		t.Fatalf("DeleteBefore: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+	if left := len(fake.Rows("pokemon_searches")); left != 4 {
+		t.Errorf("%d searches left, want 4", left)
+	}
+}
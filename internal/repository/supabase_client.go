@@ -2,10 +2,12 @@ package repository
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -25,86 +27,47 @@ func NewSupabaseClient(url, apiKey string) *SupabaseClient {
 	}
 }
 
-// Insert performs a POST request to insert data
-func (c *SupabaseClient) Insert(table string, data interface{}) ([]byte, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/%s", c.baseURL, table)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", c.apiKey)
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Prefer", "return=representation")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+// From starts a query against a table
+func (c *SupabaseClient) From(table string) *Query {
+	return &Query{
+		client: c,
+		table:  table,
+		method: http.MethodGet,
+		params: url.Values{},
 	}
+}
 
-	return body, nil
+// Result is the outcome of an executed query
+type Result struct {
+	Status int
+	Body   []byte
+	// Total is the row count from Content-Range, or -1 when no count was requested
+	Total int
 }
 
-// SelectAllPaginated performs a GET request with pagination
-func (c *SupabaseClient) SelectAllPaginated(table string, page, limit int) ([]byte, error) {
-	offset := (page - 1) * limit
-	url := fmt.Sprintf("%s/%s?select=*&order=registered_at.desc&limit=%d&offset=%d", c.baseURL, table, limit, offset)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// do executes a single request against the REST API. Every query goes
+// through here so auth headers and status handling live in one place.
+func (c *SupabaseClient) do(method, table string, params url.Values, header http.Header, body []byte) (*Result, error) {
+	endpoint := fmt.Sprintf("%s/%s", c.baseURL, url.PathEscape(table))
+	if encoded := params.Encode(); encoded != "" {
+		endpoint += "?" + encoded
 	}
 
-	req.Header.Set("apikey", c.apiKey)
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Prefer", "count=exact")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	req, err := http.NewRequest(method, endpoint, reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	for key, values := range header {
+		req.Header[key] = values
 	}
-
-	return body, nil
-}
-
-// SelectAll performs a GET request to fetch all records
-func (c *SupabaseClient) SelectAll(table string) ([]byte, error) {
-	return c.SelectAllOrdered(table, "registered_at.desc")
-}
-
-// SelectAllOrdered performs a GET request to fetch all records with custom ordering
-func (c *SupabaseClient) SelectAllOrdered(table string, order string) ([]byte, error) {
-	url := fmt.Sprintf("%s/%s?select=*&order=%s", c.baseURL, table, order)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
-
 	req.Header.Set("apikey", c.apiKey)
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
@@ -114,79 +77,54 @@ func (c *SupabaseClient) SelectAllOrdered(table string, order string) ([]byte, e
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, parseAPIError(resp.StatusCode, respBody)
 	}
 
-	return body, nil
-}
-
-// Select performs a GET request with optional filters
-func (c *SupabaseClient) Select(table, column, value string) ([]byte, error) {
-	url := fmt.Sprintf("%s/%s?%s=eq.%s&select=*", c.baseURL, table, column, value)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("apikey", c.apiKey)
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	result := &Result{Status: resp.StatusCode, Body: respBody, Total: -1}
+	if contentRange := resp.Header.Get("Content-Range"); contentRange != "" {
+		if total, err := parseContentRangeTotal(contentRange); err == nil {
+			result.Total = total
+		}
 	}
 
-	return body, nil
+	return result, nil
 }
 
-// Update performs a PATCH request
-func (c *SupabaseClient) Update(table, column, value string, updates map[string]interface{}) ([]byte, error) {
-	jsonData, err := json.Marshal(updates)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal updates: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/%s?%s=eq.%s", c.baseURL, table, column, value)
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// quoteFilterValue wraps a value in double quotes so PostgREST treats
+// reserved characters (commas, dots, parentheses) inside or=/in= lists
+// literally
+func quoteFilterValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", c.apiKey)
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Prefer", "return=representation")
+// escapeLikePattern escapes LIKE wildcards so user input matches literally.
+// PostgREST turns * into %, so it is dropped rather than escaped.
+func escapeLikePattern(value string) string {
+	value = strings.ReplaceAll(value, "*", "")
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "%", `\%`)
+	value = strings.ReplaceAll(value, "_", `\_`)
+	return value
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+// parseContentRangeTotal extracts the total from a PostgREST Content-Range
+// header such as "0-9/39" or "*/0"
+func parseContentRangeTotal(header string) (int, error) {
+	slash := strings.LastIndex(header, "/")
+	if slash < 0 {
+		return 0, fmt.Errorf("missing count in Content-Range %q", header)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	total, err := strconv.Atoi(header[slash+1:])
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return 0, fmt.Errorf("invalid count in Content-Range %q", header)
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	return body, nil
+	return total, nil
 }
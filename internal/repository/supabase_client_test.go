@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/supabasefake"
+)
+
+// testKey is the API key the fake Supabase requires in these tests
+const testKey = "test-key"
+
+// startSupabase runs the fake Supabase with the migrated tables for the
+// duration of the test and returns its URL
+func startSupabase(t *testing.T) (string, *supabasefake.Server) {
+	t.Helper()
+	srv, fake := supabasefake.Start()
+	t.Cleanup(srv.Close)
+	fake.RequireAPIKey(testKey)
+	return srv.URL, fake
+}
+
+func seed(t *testing.T, fake *supabasefake.Server, table string, rows ...map[string]interface{}) {
+	t.Helper()
+	if err := fake.Seed(table, rows...); err != nil {
+		t.Fatalf("seed %s: %v", table, err)
+	}
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestSupabaseClientAPIKey(t *testing.T) {
+	url, _ := startSupabase(t)
+
+	users := repository.NewUserRepository(url, "wrong-key")
+	_, err := users.FindAll()
+	var apiErr *repository.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != 401 {
+		t.Fatalf("FindAll with a wrong key = %v, want a 401 APIError", err)
+	}
+}
+
+func TestSupabaseClientCount(t *testing.T) {
+	url, fake := startSupabase(t)
+	seed(t, fake, "users",
+		map[string]interface{}{"telegram_id": "1", "first_name": "Ash"},
+		map[string]interface{}{"telegram_id": "2", "first_name": "Misty"},
+		map[string]interface{}{"telegram_id": "3", "first_name": "Brock"},
+	)
+
+	// Count reads the total from Content-Range on a HEAD request
+	count, err := repository.NewUserRepository(url, testKey).Count(repository.UserListOptions{})
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Count = %d, want 3", count)
+	}
+}
+
+func TestSupabaseClientNotFound(t *testing.T) {
+	url, _ := startSupabase(t)
+	client := repository.NewSupabaseClient(url, testKey)
+
+	// PGRST116: a single row was asked for and none matched
+	var row map[string]interface{}
+	_, err := client.From("users").Eq("telegram_id", "1").Single().ExecuteInto(&row)
+	if !repository.IsNotFound(err) {
+		t.Errorf("Single without rows = %v, want IsNotFound", err)
+	}
+
+	// A missing table is a 404 too, but not a missing row
+	_, err = client.From("missing").Eq("telegram_id", "1").Single().ExecuteInto(&row)
+	var apiErr *repository.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != 404 || repository.IsNotFound(err) {
+		t.Errorf("missing table = %v, want a 404 APIError that isn't IsNotFound", err)
+	}
+}
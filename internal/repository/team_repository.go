@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"fmt"
+)
+
+// TeamMember is a Pokemon on a team, stored under its PokeAPI name, with
+// the moves chosen for it
+type TeamMember struct {
+	Pokemon string   `json:"pokemon"`
+	Moves   []string `json:"moves"`
+}
+
+// Team is a named team a user built
+type Team struct {
+	ID         int          `json:"id"`
+	TelegramID string       `json:"telegram_id"`
+	Name       string       `json:"name"`
+	Members    []TeamMember `json:"members"`
+	CreatedAt  string       `json:"created_at,omitempty"`
+	UpdatedAt  string       `json:"updated_at,omitempty"`
+}
+
+// teamRow is the writable columns of a team; members is a JSONB column
+type teamRow struct {
+	TelegramID string       `json:"telegram_id"`
+	Name       string       `json:"name"`
+	Members    []TeamMember `json:"members"`
+}
+
+func (t *Team) row() teamRow {
+	members := t.Members
+	if members == nil {
+		members = []TeamMember{}
+	}
+	return teamRow{TelegramID: t.TelegramID, Name: t.Name, Members: members}
+}
+
+type TeamRepository interface {
+	Create(team *Team) error
+	FindByUser(telegramID string) ([]Team, error)
+	FindByID(telegramID string, id int) (*Team, error)
+	Update(team *Team) error
+	Delete(telegramID string, id int) error
+}
+
+type teamRepository struct {
+	client *SupabaseClient
+}
+
+func NewTeamRepository(supabaseURL, supabaseKey string) TeamRepository {
+	return &teamRepository{
+		client: NewSupabaseClient(supabaseURL, supabaseKey),
+	}
+}
+
+// Create saves a new team, filling in its id and timestamps. Team names are
+// unique per user; a taken name is ErrTeamExists.
+func (r *teamRepository) Create(team *Team) error {
+	var results []Team
+	if _, err := r.client.From("teams").Insert(team.row()).ExecuteInto(&results); err != nil {
+		if IsUniqueViolation(err) {
+			return ErrTeamExists
+		}
+		return fmt.Errorf("failed to create team: %w", err)
+	}
+	return fillTeam(team, results)
+}
+
+// FindByUser returns a user's teams, oldest first
+func (r *teamRepository) FindByUser(telegramID string) ([]Team, error) {
+	var teams []Team
+	_, err := r.client.From("teams").
+		Eq("telegram_id", telegramID).
+		Order("created_at", true).
+		Order("id", true).
+		ExecuteInto(&teams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	return teams, nil
+}
+
+// FindByID returns one of a user's teams. Teams of other users are
+// ErrTeamNotFound too.
+func (r *teamRepository) FindByID(telegramID string, id int) (*Team, error) {
+	var team Team
+	_, err := r.client.From("teams").
+		Eq("telegram_id", telegramID).
+		Eq("id", id).
+		Single().
+		ExecuteInto(&team)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+// Update replaces the name and members of a team
+func (r *teamRepository) Update(team *Team) error {
+	row := team.row()
+	updates := map[string]interface{}{
+		"name":       row.Name,
+		"members":    row.Members,
+		"updated_at": "now()",
+	}
+
+	var results []Team
+	_, err := r.client.From("teams").
+		Eq("telegram_id", team.TelegramID).
+		Eq("id", team.ID).
+		Update(updates).
+		ExecuteInto(&results)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return ErrTeamExists
+		}
+		return fmt.Errorf("failed to update team: %w", err)
+	}
+	return fillTeam(team, results)
+}
+
+func (r *teamRepository) Delete(telegramID string, id int) error {
+	var deleted []Team
+	_, err := r.client.From("teams").
+		Eq("telegram_id", telegramID).
+		Eq("id", id).
+		Delete().
+		ExecuteInto(&deleted)
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+
+	if len(deleted) == 0 {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+// fillTeam replaces team with the row a write returned; no row means the
+// team wasn't there
+func fillTeam(team *Team, rows []Team) error {
+	if len(rows) == 0 {
+		return ErrTeamNotFound
+	}
+	*team = rows[0]
+	return nil
+}
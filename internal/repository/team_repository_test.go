@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+func TestTeamCreateAndFind(t *testing.T) {
+	url, fake := startSupabase(t)
+	teams := repository.NewTeamRepository(url, testKey)
+
+	members := []repository.TeamMember{
+		{Pokemon: "pikachu", Moves: []string{"thunderbolt"}},
+		{Pokemon: "gyarados", Moves: []string{"surf", "ice-beam"}},
+	}
+	team := &repository.Team{TelegramID: "1", Name: "Main", Members: members}
+	if err := teams.Create(team); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if team.ID == 0 || team.CreatedAt == "" {
+		t.Errorf("Create didn't fill in id and created_at: %+v", team)
+	}
+
+	// Members are stored as a JSON array, not a string of one
+	stored, err := json.Marshal(fake.Rows("teams")[0]["members"])
+	if err != nil || !strings.HasPrefix(string(stored), `[{"moves":["thunderbolt"]`) {
+		t.Errorf("stored members = %s, want a JSON array", stored)
+	}
+
+	// and round-trip through the JSONB column
+	found, err := teams.FindByID("1", team.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Name != "Main" || !reflect.DeepEqual(found.Members, members) {
+		t.Errorf("FindByID = %+v, want Main with %v", found, members)
+	}
+
+	// 23505 from the unique (telegram_id, name)
+	if err := teams.Create(&repository.Team{TelegramID: "1", Name: "Main"}); !errors.Is(err, repository.ErrTeamExists) {
+		t.Errorf("Create duplicate = %v, want ErrTeamExists", err)
+	}
+	if err := teams.Create(&repository.Team{TelegramID: "2", Name: "Main"}); err != nil {
+		t.Errorf("Create with another user's name: %v", err)
+	}
+
+	// PGRST116, also for another user's team
+	if _, err := teams.FindByID("1", 99); !errors.Is(err, repository.ErrTeamNotFound) {
+		t.Errorf("FindByID unknown = %v, want ErrTeamNotFound", err)
+	}
+	if _, err := teams.FindByID("2", team.ID); !errors.Is(err, repository.ErrTeamNotFound) {
+		t.Errorf("FindByID of another user = %v, want ErrTeamNotFound", err)
+	}
+}
+
+func TestTeamFindByUser(t *testing.T) {
+	url, fake := startSupabase(t)
+	seed(t, fake, "teams",
+		map[string]interface{}{"telegram_id": "1", "name": "Rain", "created_at": "2024-03-02T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "1", "name": "Sun", "members": []map[string]interface{}{{"pokemon": "charizard", "moves": []string{}}}, "created_at": "2024-03-01T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "2", "name": "Sand", "created_at": "2024-03-01T00:00:00Z"},
+	)
+	teams := repository.NewTeamRepository(url, testKey)
+
+	list, err := teams.FindByUser("1")
+	if err != nil {
+		t.Fatalf("FindByUser: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "Sun" || list[1].Name != "Rain" {
+		t.Fatalf("FindByUser = %+v, want Sun then Rain", list)
+	}
+	if len(list[0].Members) != 1 || list[0].Members[0].Pokemon != "charizard" {
+		t.Errorf("Sun members = %+v, want charizard", list[0].Members)
+	}
+	// The column default is an empty team, not null
+	if list[1].Members == nil || len(list[1].Members) != 0 {
+		t.Errorf("Rain members = %#v, want empty", list[1].Members)
+	}
+}
+
+func TestTeamUpdateAndDelete(t *testing.T) {
+	url, _ := startSupabase(t)
+	teams := repository.NewTeamRepository(url, testKey)
+
+	main := &repository.Team{TelegramID: "1", Name: "Main"}
+	other := &repository.Team{TelegramID: "1", Name: "Other"}
+	for _, team := range []*repository.Team{main, other} {
+		if err := teams.Create(team); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	update := &repository.Team{
+		ID:         main.ID,
+		TelegramID: "1",
+		Name:       "Renamed",
+		Members:    []repository.TeamMember{{Pokemon: "eevee", Moves: []string{}}},
+	}
+	if err := teams.Update(update); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if update.Name != "Renamed" || update.CreatedAt != main.CreatedAt || len(update.Members) != 1 {
+		t.Errorf("Update = %+v", update)
+	}
+
+	clash := &repository.Team{ID: main.ID, TelegramID: "1", Name: "Other"}
+	if err := teams.Update(clash); !errors.Is(err, repository.ErrTeamExists) {
+		t.Errorf("Update to a taken name = %v, want ErrTeamExists", err)
+	}
+	missing := &repository.Team{ID: main.ID, TelegramID: "2", Name: "Mine"}
+	if err := teams.Update(missing); !errors.Is(err, repository.ErrTeamNotFound) {
+		t.Errorf("Update of another user's team = %v, want ErrTeamNotFound", err)
+	}
+
+	if err := teams.Delete("1", main.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := teams.Delete("1", main.ID); !errors.Is(err, repository.ErrTeamNotFound) {
+		t.Errorf("Delete twice = %v, want ErrTeamNotFound", err)
+	}
+}
@@ -1,8 +1,9 @@
 package repository
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/yourusername/pokemon-chatbot-api/internal/models"
 )
@@ -11,9 +12,93 @@ type UserRepository interface {
 	Create(user *models.User) error
 	FindByTelegramID(telegramID string) (*models.User, error)
 	FindAll() ([]models.User, error)
-	FindAllPaginated(page, limit int) ([]models.User, error)
-	Count() (int, error)
+	FindAllPaginated(opts UserListOptions, page, limit int) ([]models.User, int, error)
+	FindAllAfter(opts UserListOptions, after *Cursor, limit int) ([]models.User, *Cursor, error)
+	Count(opts UserListOptions) (int, error)
 	UpdateLastActive(telegramID string) error
+	UpdateLanguage(telegramID, language string) (*models.User, error)
+	Delete(telegramID string) error
+}
+
+// Sortable user list columns
+const (
+	SortRegisteredAt = "registered_at"
+	SortLastActive   = "last_active"
+	SortFirstName    = "first_name"
+)
+
+// UserListOptions filters and sorts the paginated user list
+type UserListOptions struct {
+	Query          string // substring match on first name, last name or username
+	RegisteredFrom *time.Time
+	RegisteredTo   *time.Time
+	ActiveSince    *time.Time
+	SortBy         string // one of the Sort* columns, defaults to registered_at
+	SortAsc        bool
+}
+
+// IsValidUserSort reports whether column can be used to sort the user list
+func IsValidUserSort(column string) bool {
+	switch column {
+	case SortRegisteredAt, SortLastActive, SortFirstName:
+		return true
+	}
+	return false
+}
+
+// filter adds the option filters to q
+func (o UserListOptions) filter(q *Query) *Query {
+	if term := escapeLikePattern(strings.TrimSpace(o.Query)); term != "" {
+		pattern := quoteFilterValue("*" + term + "*")
+		q.Or(fmt.Sprintf("(first_name.ilike.%s,last_name.ilike.%s,username.ilike.%s)",
+			pattern, pattern, pattern))
+	}
+	if o.RegisteredFrom != nil {
+		q.Gte("registered_at", *o.RegisteredFrom)
+	}
+	if o.RegisteredTo != nil {
+		q.Lte("registered_at", *o.RegisteredTo)
+	}
+	if o.ActiveSince != nil {
+		q.Gte("last_active", *o.ActiveSince)
+	}
+	return q
+}
+
+// order sorts q by the option sort column, with id breaking ties so pages
+// stay stable when sort values repeat
+func (o UserListOptions) order(q *Query) *Query {
+	return q.OrderNullsLast(o.sortColumn(), o.SortAsc).Order("id", o.SortAsc)
+}
+
+func (o UserListOptions) sortColumn() string {
+	if !IsValidUserSort(o.SortBy) {
+		return SortRegisteredAt
+	}
+	return o.SortBy
+}
+
+// cursorFor builds the cursor pointing just past user in the given ordering
+func (o UserListOptions) cursorFor(user models.User) *Cursor {
+	cursor := &Cursor{SortBy: o.sortColumn(), Asc: o.SortAsc, ID: user.ID}
+
+	var at *time.Time
+	switch cursor.SortBy {
+	case SortFirstName:
+		cursor.Value = user.FirstName
+		return cursor
+	case SortLastActive:
+		at = user.LastActive
+	default:
+		at = user.RegisteredAt
+	}
+
+	if at == nil {
+		cursor.Null = true
+	} else {
+		cursor.Value = at.UTC().Format(time.RFC3339Nano)
+	}
+	return cursor
 }
 
 type userRepository struct {
@@ -27,14 +112,12 @@ func NewUserRepository(supabaseURL, supabaseKey string) UserRepository {
 }
 
 func (r *userRepository) Create(user *models.User) error {
-	body, err := r.client.Insert("users", user)
-	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
-	}
-
 	var results []models.User
-	if err := json.Unmarshal(body, &results); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+	if _, err := r.client.From("users").Insert(user).ExecuteInto(&results); err != nil {
+		if IsUniqueViolation(err) {
+			return ErrUserExists
+		}
+		return fmt.Errorf("failed to create user: %w", err)
 	}
 
 	if len(results) > 0 {
@@ -45,57 +128,76 @@ func (r *userRepository) Create(user *models.User) error {
 }
 
 func (r *userRepository) FindByTelegramID(telegramID string) (*models.User, error) {
-	body, err := r.client.Select("users", "telegram_id", telegramID)
-	if err != nil {
+	var user models.User
+	if _, err := r.client.From("users").Eq("telegram_id", telegramID).Single().ExecuteInto(&user); err != nil {
+		if IsNotFound(err) {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
-	var results []models.User
-	if err := json.Unmarshal(body, &results); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if len(results) == 0 {
-		return nil, fmt.Errorf("user not found")
-	}
-
-	return &results[0], nil
+	return &user, nil
 }
 
 func (r *userRepository) FindAll() ([]models.User, error) {
-	body, err := r.client.SelectAll("users")
-	if err != nil {
+	var users []models.User
+	if _, err := r.client.From("users").Order("registered_at", false).ExecuteInto(&users); err != nil {
 		return nil, err
 	}
 
+	return users, nil
+}
+
+// FindAllPaginated returns one page of users together with the total number
+// of users matching opts, in a single round-trip
+func (r *userRepository) FindAllPaginated(opts UserListOptions, page, limit int) ([]models.User, int, error) {
+	q := r.client.From("users").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Count(CountExact)
+
 	var users []models.User
-	if err := json.Unmarshal(body, &users); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	result, err := opts.order(opts.filter(q)).ExecuteInto(&users)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return users, nil
+	return users, result.Total, nil
 }
 
-func (r *userRepository) FindAllPaginated(page, limit int) ([]models.User, error) {
-	body, err := r.client.SelectAllPaginated("users", page, limit)
-	if err != nil {
-		return nil, err
+// FindAllAfter returns up to limit users following the after cursor (or from
+// the start when nil) using keyset pagination. The returned cursor is nil on
+// the last page. A cursor carries its own ordering, which overrides opts.
+func (r *userRepository) FindAllAfter(opts UserListOptions, after *Cursor, limit int) ([]models.User, *Cursor, error) {
+	q := opts.filter(r.client.From("users"))
+	if after != nil {
+		if !IsValidUserSort(after.SortBy) {
+			return nil, nil, ErrInvalidCursor
+		}
+		opts.SortBy = after.SortBy
+		opts.SortAsc = after.Asc
+		after.after(q)
 	}
 
+	// Fetch one extra row to learn whether another page exists
 	var users []models.User
-	if err := json.Unmarshal(body, &users); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if _, err := opts.order(q).Limit(limit + 1).ExecuteInto(&users); err != nil {
+		return nil, nil, err
 	}
 
-	return users, nil
+	if len(users) <= limit {
+		return users, nil, nil
+	}
+	users = users[:limit]
+	return users, opts.cursorFor(users[limit-1]), nil
 }
 
-func (r *userRepository) Count() (int, error) {
-	users, err := r.FindAll()
+func (r *userRepository) Count(opts UserListOptions) (int, error) {
+	result, err := opts.filter(r.client.From("users")).Head().Count(CountExact).Execute()
 	if err != nil {
 		return 0, err
 	}
-	return len(users), nil
+	return result.Total, nil
 }
 
 func (r *userRepository) UpdateLastActive(telegramID string) error {
@@ -103,6 +205,37 @@ func (r *userRepository) UpdateLastActive(telegramID string) error {
 		"last_active": "now()",
 	}
 
-	_, err := r.client.Update("users", "telegram_id", telegramID, updates)
+	_, err := r.client.From("users").Eq("telegram_id", telegramID).Update(updates).Execute()
 	return err
 }
+
+// UpdateLanguage stores a user's preferred language and returns the updated
+// user
+func (r *userRepository) UpdateLanguage(telegramID, language string) (*models.User, error) {
+	updates := map[string]interface{}{
+		"language": language,
+	}
+
+	var updated []models.User
+	if _, err := r.client.From("users").Eq("telegram_id", telegramID).Update(updates).ExecuteInto(&updated); err != nil {
+		return nil, fmt.Errorf("failed to update language: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, ErrUserNotFound
+	}
+	return &updated[0], nil
+}
+
+func (r *userRepository) Delete(telegramID string) error {
+	var deleted []models.User
+	if _, err := r.client.From("users").Eq("telegram_id", telegramID).Delete().ExecuteInto(&deleted); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if len(deleted) == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
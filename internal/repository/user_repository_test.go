@@ -0,0 +1,221 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/models"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/supabasefake"
+)
+
+// seedUsers registers five users a day apart. Brock has never been active.
+func seedUsers(t *testing.T, fake *supabasefake.Server) {
+	t.Helper()
+	seed(t, fake, "users",
+		map[string]interface{}{"telegram_id": "1", "first_name": "Ash", "last_name": "Ketchum", "username": "ash", "registered_at": "2024-01-01T00:00:00Z", "last_active": "2024-02-05T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "2", "first_name": "Misty", "username": "cerulean", "registered_at": "2024-01-02T00:00:00Z", "last_active": "2024-02-01T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "3", "first_name": "Brock", "username": "pewter_rock", "registered_at": "2024-01-03T00:00:00Z", "last_active": nil},
+		map[string]interface{}{"telegram_id": "4", "first_name": "Gary", "last_name": "Oak", "registered_at": "2024-01-04T00:00:00Z", "last_active": "2024-02-01T00:00:00Z"},
+		map[string]interface{}{"telegram_id": "5", "first_name": "Ashley", "username": "ash_50%", "registered_at": "2024-01-05T00:00:00Z", "last_active": "2024-02-03T00:00:00Z"},
+	)
+}
+
+func telegramIDs(users []models.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.TelegramID
+	}
+	return ids
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestUserCreateAndFind(t *testing.T) {
+	url, _ := startSupabase(t)
+	users := repository.NewUserRepository(url, testKey)
+
+	user := &models.User{TelegramID: "42", FirstName: "Ash", Username: "ash"}
+	if err := users.Create(user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.ID == 0 || user.RegisteredAt == nil {
+		t.Errorf("Create didn't fill in id and registered_at: %+v", user)
+	}
+
+	found, err := users.FindByTelegramID("42")
+	if err != nil {
+		t.Fatalf("FindByTelegramID: %v", err)
+	}
+	if found.ID != user.ID || found.FirstName != "Ash" {
+		t.Errorf("FindByTelegramID = %+v, want %+v", found, user)
+	}
+
+	// 23505 from the unique telegram_id
+	if err := users.Create(&models.User{TelegramID: "42", FirstName: "Again"}); !errors.Is(err, repository.ErrUserExists) {
+		t.Errorf("Create duplicate = %v, want ErrUserExists", err)
+	}
+	// PGRST116 from Single() matching no row
+	if _, err := users.FindByTelegramID("7"); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("FindByTelegramID unknown = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestUserUpdateAndDelete(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedUsers(t, fake)
+	users := repository.NewUserRepository(url, testKey)
+
+	updated, err := users.UpdateLanguage("2", "id")
+	if err != nil {
+		t.Fatalf("UpdateLanguage: %v", err)
+	}
+	if updated.Language != "id" || updated.FirstName != "Misty" {
+		t.Errorf("UpdateLanguage = %+v", updated)
+	}
+	if _, err := users.UpdateLanguage("99", "id"); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("UpdateLanguage unknown = %v, want ErrUserNotFound", err)
+	}
+
+	if err := users.Delete("2"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := users.Delete("2"); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("Delete twice = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestUserFindAllPaginated(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedUsers(t, fake)
+	users := repository.NewUserRepository(url, testKey)
+
+	from, to := at("2024-01-02T00:00:00Z"), at("2024-01-04T00:00:00Z")
+	since := at("2024-02-02T00:00:00Z")
+	tests := []struct {
+		name  string
+		opts  repository.UserListOptions
+		page  int
+		want  []string
+		total int
+	}{
+		{"newest first", repository.UserListOptions{}, 1, []string{"5", "4"}, 5},
+		{"second page", repository.UserListOptions{}, 2, []string{"3", "2"}, 5},
+		{"last page", repository.UserListOptions{}, 3, []string{"1"}, 5},
+		{"by first name", repository.UserListOptions{SortBy: repository.SortFirstName, SortAsc: true}, 1, []string{"1", "5"}, 5},
+		// The or tree matches first name, last name or username, ignoring case
+		{"query first name", repository.UserListOptions{Query: "ASH"}, 1, []string{"5", "1"}, 2},
+		{"query last name", repository.UserListOptions{Query: "oak"}, 1, []string{"4"}, 1},
+		{"query username", repository.UserListOptions{Query: "rock"}, 1, []string{"3"}, 1},
+		// Wildcards in the query are literal
+		{"query percent", repository.UserListOptions{Query: "50%"}, 1, []string{"5"}, 1},
+		{"query underscore", repository.UserListOptions{Query: "h_5"}, 1, []string{"5"}, 1},
+		{"registered between", repository.UserListOptions{RegisteredFrom: &from, RegisteredTo: &to}, 1, []string{"4", "3"}, 3},
+		{"active since", repository.UserListOptions{ActiveSince: &since}, 1, []string{"5", "1"}, 2},
+		{"query and window", repository.UserListOptions{Query: "ash", RegisteredFrom: &to}, 1, []string{"5"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, total, err := users.FindAllPaginated(tt.opts, tt.page, 2)
+			if err != nil {
+				t.Fatalf("FindAllPaginated: %v", err)
+			}
+			if got := telegramIDs(page); !equalIDs(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+			if total != tt.total {
+				t.Errorf("total = %d, want %d", total, tt.total)
+			}
+
+			count, err := users.Count(tt.opts)
+			if err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if count != tt.total {
+				t.Errorf("Count = %d, want %d", count, tt.total)
+			}
+		})
+	}
+}
+
+func TestUserFindAllAfter(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedUsers(t, fake)
+	users := repository.NewUserRepository(url, testKey)
+
+	tests := []struct {
+		name string
+		opts repository.UserListOptions
+		want []string
+	}{
+		{"registered desc", repository.UserListOptions{}, []string{"5", "4", "3", "2", "1"}},
+		{"registered asc", repository.UserListOptions{SortAsc: true}, []string{"1", "2", "3", "4", "5"}},
+		{"first name", repository.UserListOptions{SortBy: repository.SortFirstName, SortAsc: true}, []string{"1", "5", "3", "4", "2"}},
+		// Ties on last_active are broken by id; Brock's null sorts last
+		{"last active desc", repository.UserListOptions{SortBy: repository.SortLastActive}, []string{"1", "5", "4", "2", "3"}},
+		{"last active asc", repository.UserListOptions{SortBy: repository.SortLastActive, SortAsc: true}, []string{"2", "4", "5", "1", "3"}},
+		{"filtered", repository.UserListOptions{Query: "ash", SortAsc: true}, []string{"1", "5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var after *repository.Cursor
+			for pages := 0; ; pages++ {
+				if pages > len(tt.want) {
+					t.Fatalf("paging didn't end after %d pages", pages)
+				}
+				page, next, err := users.FindAllAfter(tt.opts, after, 2)
+				if err != nil {
+					t.Fatalf("FindAllAfter: %v", err)
+				}
+				got = append(got, telegramIDs(page)...)
+				if next == nil {
+					break
+				}
+
+				// Cursors travel opaque, as the dashboard API hands them out
+				after, err = repository.DecodeCursor(repository.EncodeCursor(*next))
+				if err != nil {
+					t.Fatalf("DecodeCursor: %v", err)
+				}
+			}
+			if !equalIDs(got, tt.want) {
+				t.Errorf("pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	bad := &repository.Cursor{SortBy: "username", ID: 1}
+	if _, _, err := users.FindAllAfter(repository.UserListOptions{}, bad, 2); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("FindAllAfter with an unsortable cursor = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestUserUpdateLastActive(t *testing.T) {
+	url, fake := startSupabase(t)
+	seedUsers(t, fake)
+	users := repository.NewUserRepository(url, testKey)
+
+	before := time.Now().Add(-time.Minute)
+	if err := users.UpdateLastActive("3"); err != nil {
+		t.Fatalf("UpdateLastActive: %v", err)
+	}
+	user, err := users.FindByTelegramID("3")
+	if err != nil {
+		t.Fatalf("FindByTelegramID: %v", err)
+	}
+	if user.LastActive == nil || user.LastActive.Before(before) {
+		t.Errorf("last_active = %v, want about now", user.LastActive)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PokemonAbility is one of a Pokemon's abilities. ShortEffect is left
+// empty when the ability can't be fetched.
+type PokemonAbility struct {
+	Name        string `json:"name"`
+	Ability     string `json:"ability"`
+	IsHidden    bool   `json:"isHidden"`
+	Slot        int    `json:"slot"`
+	ShortEffect string `json:"shortEffect,omitempty"`
+}
+
+type AbilityResponse struct {
+	Found   bool         `json:"found"`
+	Message string       `json:"message"`
+	Data    *AbilityData `json:"data,omitempty"`
+}
+
+type AbilityData struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Effect      string          `json:"effect"`
+	ShortEffect string          `json:"short_effect"`
+	FlavorText  string          `json:"flavor_text,omitempty"`
+	Generation  int             `json:"generation"`
+	Pokemon     []AbilityHolder `json:"pokemon"`
+}
+
+// AbilityHolder is a Pokemon that can have the ability
+type AbilityHolder struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	IsHidden bool   `json:"is_hidden"`
+	Slot     int    `json:"slot"`
+}
+
+// apiAbility is the part of an ability resource the service reads
+type apiAbility struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	Generation    namedResource `json:"generation"`
+	Names         []localName   `json:"names"`
+	EffectEntries []struct {
+		Effect      string        `json:"effect"`
+		ShortEffect string        `json:"short_effect"`
+		Language    namedResource `json:"language"`
+	} `json:"effect_entries"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   namedResource `json:"language"`
+	} `json:"flavor_text_entries"`
+	Pokemon []struct {
+		IsHidden bool          `json:"is_hidden"`
+		Slot     int           `json:"slot"`
+		Pokemon  namedResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+// maxAbilityHolders caps the Pokemon named in an ability message
+const maxAbilityHolders = 10
+
+// fetchAbility loads an ability resource by name or id
+func (s *pokemonService) fetchAbility(nameOrID string) (*apiAbility, error) {
+	body, err := s.fetch("ability", nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	var ability apiAbility
+	if err := json.Unmarshal(body, &ability); err != nil {
+		return nil, err
+	}
+	return &ability, nil
+}
+
+// GetAbility returns an ability's effect and the Pokemon that can have it.
+// Names may use spaces ("Lightning Rod") or PokeAPI slugs.
+func (s *pokemonService) GetAbility(nameOrID string) (*AbilityResponse, error) {
+	slug := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(nameOrID)), " ", "-")
+	ability, err := s.fetchAbility(slug)
+	if errors.Is(err, errResourceNotFound) {
+		return &AbilityResponse{
+			Found:   false,
+			Message: fmt.Sprintf("Sorry we don't have information for ability <%s>", nameOrID),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	effect, shortEffect := ability.effects()
+	data := &AbilityData{
+		ID:          ability.ID,
+		Name:        ability.displayName(),
+		Effect:      effect,
+		ShortEffect: shortEffect,
+		FlavorText:  ability.flavorText(),
+		Generation:  generationNumber(ability.Generation.Name),
+		Pokemon:     make([]AbilityHolder, len(ability.Pokemon)),
+	}
+	for i, p := range ability.Pokemon {
+		id, _ := strconv.Atoi(p.Pokemon.id())
+		data.Pokemon[i] = AbilityHolder{
+			ID:       id,
+			Name:     capitalize(p.Pokemon.Name),
+			IsHidden: p.IsHidden,
+			Slot:     p.Slot,
+		}
+	}
+
+	return &AbilityResponse{
+		Found:   true,
+		Message: abilityMessage(data),
+		Data:    data,
+	}, nil
+}
+
+// describeAbilities fills in the short effect of each ability, and its
+// name in lang, fetching them concurrently. Abilities that fail to load
+// keep an empty effect.
+func (s *pokemonService) describeAbilities(abilities []PokemonAbility, lang string) {
+	var wg sync.WaitGroup
+	for i := range abilities {
+		wg.Add(1)
+		go func(a *PokemonAbility) {
+			defer wg.Done()
+			ability, err := s.fetchAbility(a.Ability)
+			if err != nil {
+				return
+			}
+			_, a.ShortEffect = ability.effects()
+			if name := pickName(ability.Names, lang); localizes(lang) && name != "" {
+				a.Name = name
+			}
+		}(&abilities[i])
+	}
+	wg.Wait()
+}
+
+func (a *apiAbility) displayName() string {
+	if name := pickName(a.Names, "en"); name != "" {
+		return name
+	}
+	return titleCase(a.Name)
+}
+
+// effects returns the English effect and short effect, each on one line
+func (a *apiAbility) effects() (string, string) {
+	for _, e := range a.EffectEntries {
+		if e.Language.Name == "en" {
+			return strings.Join(strings.Fields(e.Effect), " "), strings.Join(strings.Fields(e.ShortEffect), " ")
+		}
+	}
+	return "", ""
+}
+
+// flavorText returns the most recent English description on one line
+func (a *apiAbility) flavorText() string {
+	text := ""
+	for _, entry := range a.FlavorTextEntries {
+		if entry.Language.Name == "en" {
+			text = entry.FlavorText
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// abilityMessage summarises an ability, e.g. "Static: Has a 30% chance of
+// paralyzing attacking Pokémon on contact. Pokemon with Static: Pikachu,
+// Raichu, ..."
+func abilityMessage(data *AbilityData) string {
+	message := data.Name
+	if data.ShortEffect != "" {
+		message += ": " + data.ShortEffect
+	} else {
+		message += "."
+	}
+	if len(data.Pokemon) == 0 {
+		return message
+	}
+
+	names := make([]string, 0, maxAbilityHolders+1)
+	for i, p := range data.Pokemon {
+		if i == maxAbilityHolders {
+			names = append(names, fmt.Sprintf("%d more", len(data.Pokemon)-maxAbilityHolders))
+			break
+		}
+		name := p.Name
+		if p.IsHidden {
+			name += " (hidden)"
+		}
+		names = append(names, name)
+	}
+	return fmt.Sprintf("%s Pokemon with %s: %s.", message, data.Name, joinAnd(names))
+}
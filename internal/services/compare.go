@@ -0,0 +1,281 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+)
+
+// Bounds on how many Pokemon one comparison takes
+const (
+	MinCompare = 2
+	MaxCompare = 6
+)
+
+// ErrTooFewToCompare is returned when the names given to ComparePokemon
+// resolve to fewer than MinCompare different Pokemon, as "pikachu,25" does
+var ErrTooFewToCompare = fmt.Errorf("compare takes at least %d different Pokemon", MinCompare)
+
+type CompareResponse struct {
+	Found    bool         `json:"found"`
+	Message  string       `json:"message"`
+	NotFound []string     `json:"not_found,omitempty"`
+	Data     *CompareData `json:"data,omitempty"`
+	// Suggestions maps each not-found name to close names
+	Suggestions map[string][]PokemonSuggestion `json:"suggestions,omitempty"`
+}
+
+// CompareData puts several Pokemon side by side. Winners maps each stat
+// (named as in PokemonStats, plus "total") to the Pokemon with the highest
+// value, several on a tie.
+type CompareData struct {
+	Pokemon    []ComparedPokemon   `json:"pokemon"`
+	Winners    map[string][]string `json:"winners"`
+	Advantages []TypeAdvantage     `json:"advantages"`
+}
+
+type ComparedPokemon struct {
+	ID     int          `json:"id"`
+	Name   string       `json:"name"`
+	Types  []string     `json:"types"`
+	Sprite string       `json:"sprite"`
+	Stats  PokemonStats `json:"stats"`
+	Total  int          `json:"total"`
+}
+
+// TypeAdvantage is the best multiplier the attacker's own types (same-type
+// moves) deal to the defender. Every ordered pair is listed.
+type TypeAdvantage struct {
+	Attacker   string  `json:"attacker"`
+	Defender   string  `json:"defender"`
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// statOrder lists the compared stats with their display names
+var statOrder = []struct {
+	key, label string
+	value      func(PokemonStats) int
+}{
+	{"hp", "HP", func(s PokemonStats) int { return s.HP }},
+	{"attack", "Attack", func(s PokemonStats) int { return s.Attack }},
+	{"defense", "Defense", func(s PokemonStats) int { return s.Defense }},
+	{"spAttack", "Sp. Atk", func(s PokemonStats) int { return s.SpAttack }},
+	{"spDefense", "Sp. Def", func(s PokemonStats) int { return s.SpDefense }},
+	{"speed", "Speed", func(s PokemonStats) int { return s.Speed }},
+}
+
+// ComparePokemon fetches the given Pokemon concurrently and compares their
+// base stats and type matchups. Comparisons are not logged as searches.
+func (s *pokemonService) ComparePokemon(names []string) (*CompareResponse, error) {
+	if len(names) < MinCompare || len(names) > MaxCompare {
+		return nil, fmt.Errorf("compare takes between %d and %d Pokemon", MinCompare, MaxCompare)
+	}
+
+	type result struct {
+		pokemon *ComparedPokemon
+		err     error
+	}
+	results := make([]result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			p, err := s.comparedPokemon(name)
+			results[i] = result{p, err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	var notFound []string
+	pokemon := make([]ComparedPokemon, 0, len(names))
+	seen := make(map[int]bool)
+	for i, r := range results {
+		switch {
+		case errors.Is(r.err, errResourceNotFound):
+			notFound = append(notFound, names[i])
+		case r.err != nil:
+			return nil, r.err
+		case !seen[r.pokemon.ID]:
+			// Names, ids and localized names of one Pokemon count once
+			seen[r.pokemon.ID] = true
+			pokemon = append(pokemon, *r.pokemon)
+		}
+	}
+	if len(notFound) > 0 {
+		suggestions := make(map[string][]PokemonSuggestion)
+		for _, name := range notFound {
+			if similar := s.suggest(name); len(similar) > 0 {
+				suggestions[name] = similar
+			}
+		}
+		return &CompareResponse{
+			Found:       false,
+			Message:     fmt.Sprintf("Sorry we don't have information for <%s>", strings.Join(notFound, ">, <")),
+			NotFound:    notFound,
+			Suggestions: suggestions,
+		}, nil
+	}
+
+	if len(pokemon) < MinCompare {
+		return nil, ErrTooFewToCompare
+	}
+
+	data := &CompareData{
+		Pokemon:    pokemon,
+		Winners:    statWinners(pokemon),
+		Advantages: typeAdvantages(pokemon),
+	}
+	return &CompareResponse{
+		Found:   true,
+		Message: compareMessage(data),
+		Data:    data,
+	}, nil
+}
+
+func (s *pokemonService) comparedPokemon(nameOrID string) (*ComparedPokemon, error) {
+	// Resolved like GetPokemon, so localized names compare too
+	body, err := s.fetchPokemon(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawData map[string]interface{}
+	if err := json.Unmarshal(body, &rawData); err != nil {
+		return nil, err
+	}
+	var typing apiPokemonTypes
+	if err := json.Unmarshal(body, &typing); err != nil {
+		return nil, err
+	}
+
+	data, err := s.transformData(rawData)
+	if err != nil {
+		return nil, err
+	}
+	p := &ComparedPokemon{
+		ID:     data.ID,
+		Name:   data.Name,
+		Types:  typing.typesIn(0),
+		Sprite: data.Sprite,
+		Stats:  data.Stats,
+		Total:  data.Stats.Total(),
+	}
+	return p, nil
+}
+
+func statWinners(pokemon []ComparedPokemon) map[string][]string {
+	winners := make(map[string][]string, len(statOrder)+1)
+	pick := func(key string, value func(ComparedPokemon) int) {
+		best := -1
+		for _, p := range pokemon {
+			switch v := value(p); {
+			case v > best:
+				best = v
+				winners[key] = []string{p.Name}
+			case v == best:
+				winners[key] = append(winners[key], p.Name)
+			}
+		}
+	}
+	for _, stat := range statOrder {
+		stat := stat
+		pick(stat.key, func(p ComparedPokemon) int { return stat.value(p.Stats) })
+	}
+	pick("total", func(p ComparedPokemon) int { return p.Total })
+	return winners
+}
+
+func typeAdvantages(pokemon []ComparedPokemon) []TypeAdvantage {
+	chart := typechart.Latest()
+	advantages := []TypeAdvantage{}
+	for _, attacker := range pokemon {
+		for _, defender := range pokemon {
+			if attacker.Name == defender.Name {
+				continue
+			}
+			best := TypeAdvantage{Attacker: attacker.Name, Defender: defender.Name, Multiplier: -1}
+			for _, t := range attacker.Types {
+				if m := chart.Against(t, defender.Types...); m > best.Multiplier {
+					best.Type, best.Multiplier = t, m
+				}
+			}
+			advantages = append(advantages, best)
+		}
+	}
+	return advantages
+}
+
+// compareMessage summarises the comparison for chat
+func compareMessage(data *CompareData) string {
+	ranked := append([]ComparedPokemon(nil), data.Pokemon...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Total > ranked[j].Total })
+
+	var sentences []string
+	if len(ranked) == 2 {
+		if ranked[0].Total == ranked[1].Total {
+			sentences = append(sentences, fmt.Sprintf("%s and %s have the same base stat total (%d).",
+				ranked[0].Name, ranked[1].Name, ranked[0].Total))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("%s has the higher base stat total (%d vs %d).",
+				ranked[0].Name, ranked[0].Total, ranked[1].Total))
+		}
+	} else {
+		rest := make([]string, len(ranked)-1)
+		for i, p := range ranked[1:] {
+			rest[i] = fmt.Sprintf("%s (%d)", p.Name, p.Total)
+		}
+		sentences = append(sentences, fmt.Sprintf("%s has the highest base stat total (%d), followed by %s.",
+			ranked[0].Name, ranked[0].Total, joinAnd(rest)))
+	}
+
+	// Group the stats each Pokemon leads, in stat order
+	leads := make(map[string][]string)
+	var leaders []string
+	for _, stat := range statOrder {
+		winners := data.Winners[stat.key]
+		if len(winners) != 1 {
+			continue
+		}
+		if _, seen := leads[winners[0]]; !seen {
+			leaders = append(leaders, winners[0])
+		}
+		leads[winners[0]] = append(leads[winners[0]], stat.label)
+	}
+	for _, name := range leaders {
+		sentences = append(sentences, fmt.Sprintf("%s leads in %s.", name, joinAnd(leads[name])))
+	}
+
+	var edges []string
+	for _, a := range data.Advantages {
+		if a.Multiplier > 1 {
+			edges = append(edges, fmt.Sprintf("%s's %s moves hit %s for %sx",
+				a.Attacker, capitalize(a.Type), a.Defender, strconv.FormatFloat(a.Multiplier, 'f', -1, 64)))
+		}
+	}
+	switch {
+	case len(edges) > 0:
+		sentences = append(sentences, "Type-wise, "+joinAnd(edges)+".")
+	case len(ranked) == 2:
+		sentences = append(sentences, "Neither has a type advantage.")
+	default:
+		sentences = append(sentences, "None has a type advantage.")
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// joinAnd joins items as "a, b and c"
+func joinAnd(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type EvolutionResponse struct {
+	Found   bool           `json:"found"`
+	Message string         `json:"message"`
+	Data    *EvolutionData `json:"data,omitempty"`
+}
+
+// EvolutionData is the evolution tree of a species' family. Chain is the
+// first stage; Pokemon names the stage that was asked for.
+type EvolutionData struct {
+	ID      int           `json:"id"`
+	Pokemon string        `json:"pokemon"`
+	Chain   EvolutionNode `json:"chain"`
+}
+
+// EvolutionNode is one species in the tree. Conditions describe how it
+// evolves from its parent; there are several when games differ.
+type EvolutionNode struct {
+	ID         int                  `json:"id"`
+	Name       string               `json:"name"`
+	IsBaby     bool                 `json:"is_baby,omitempty"`
+	Sprite     string               `json:"sprite"`
+	Conditions []EvolutionCondition `json:"conditions,omitempty"`
+	EvolvesTo  []EvolutionNode      `json:"evolves_to"`
+}
+
+// EvolutionCondition is one way to trigger an evolution. Only the fields
+// that apply are set; Description summarises them.
+type EvolutionCondition struct {
+	Trigger        string `json:"trigger"`
+	Description    string `json:"description"`
+	MinLevel       *int   `json:"min_level,omitempty"`
+	Item           string `json:"item,omitempty"`
+	HeldItem       string `json:"held_item,omitempty"`
+	MinHappiness   *int   `json:"min_happiness,omitempty"`
+	MinAffection   *int   `json:"min_affection,omitempty"`
+	MinBeauty      *int   `json:"min_beauty,omitempty"`
+	TimeOfDay      string `json:"time_of_day,omitempty"`
+	KnownMove      string `json:"known_move,omitempty"`
+	KnownMoveType  string `json:"known_move_type,omitempty"`
+	Location       string `json:"location,omitempty"`
+	Gender         string `json:"gender,omitempty"`
+	NeedsRain      bool   `json:"needs_rain,omitempty"`
+	PartySpecies   string `json:"party_species,omitempty"`
+	PartyType      string `json:"party_type,omitempty"`
+	TradeSpecies   string `json:"trade_species,omitempty"`
+	PhysicalStats  string `json:"physical_stats,omitempty"`
+	TurnUpsideDown bool   `json:"turn_upside_down,omitempty"`
+}
+
+// apiChainLink is a node of a PokeAPI evolution-chain resource
+type apiChainLink struct {
+	Species          namedResource        `json:"species"`
+	IsBaby           bool                 `json:"is_baby"`
+	EvolutionDetails []apiEvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []apiChainLink       `json:"evolves_to"`
+}
+
+type apiEvolutionDetail struct {
+	Trigger               namedResource  `json:"trigger"`
+	MinLevel              *int           `json:"min_level"`
+	Item                  *namedResource `json:"item"`
+	HeldItem              *namedResource `json:"held_item"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinAffection          *int           `json:"min_affection"`
+	MinBeauty             *int           `json:"min_beauty"`
+	TimeOfDay             string         `json:"time_of_day"`
+	KnownMove             *namedResource `json:"known_move"`
+	KnownMoveType         *namedResource `json:"known_move_type"`
+	Location              *namedResource `json:"location"`
+	Gender                *int           `json:"gender"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	PartySpecies          *namedResource `json:"party_species"`
+	PartyType             *namedResource `json:"party_type"`
+	TradeSpecies          *namedResource `json:"trade_species"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+// GetEvolution returns the evolution tree of the family a Pokemon belongs
+// to. Forms such as "deoxys-attack" resolve through their species.
+func (s *pokemonService) GetEvolution(nameOrID string) (*EvolutionResponse, error) {
+	species, err := s.fetchSpecies(nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		species, err = s.speciesOfPokemon(nameOrID)
+	}
+	if errors.Is(err, errResourceNotFound) {
+		return &EvolutionResponse{
+			Found:   false,
+			Message: fmt.Sprintf("Sorry we don't have information for <%s>", nameOrID),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := namedResource{URL: species.EvolutionChain.URL}.id()
+	body, err := s.fetch("evolution-chain", chainID)
+	if err != nil {
+		return nil, err
+	}
+	var chain struct {
+		ID    int          `json:"id"`
+		Chain apiChainLink `json:"chain"`
+	}
+	if err := json.Unmarshal(body, &chain); err != nil {
+		return nil, err
+	}
+
+	data := &EvolutionData{
+		ID:      chain.ID,
+		Pokemon: capitalize(species.Name),
+		Chain:   buildEvolutionNode(chain.Chain),
+	}
+	return &EvolutionResponse{
+		Found:   true,
+		Message: evolutionMessage(&data.Chain, nil, data.Pokemon),
+		Data:    data,
+	}, nil
+}
+
+// speciesOfPokemon follows a pokemon resource to its species, for names
+// that only exist as pokemon (alternate forms)
+func (s *pokemonService) speciesOfPokemon(nameOrID string) (*apiSpecies, error) {
+	body, err := s.fetch("pokemon", nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	var pokemon struct {
+		Species namedResource `json:"species"`
+	}
+	if err := json.Unmarshal(body, &pokemon); err != nil {
+		return nil, err
+	}
+	return s.fetchSpecies(pokemon.Species.Name)
+}
+
+func buildEvolutionNode(link apiChainLink) EvolutionNode {
+	id, _ := strconv.Atoi(link.Species.id())
+	node := EvolutionNode{
+		ID:        id,
+		Name:      capitalize(link.Species.Name),
+		IsBaby:    link.IsBaby,
+		Sprite:    fmt.Sprintf("https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/official-artwork/%d.png", id),
+		EvolvesTo: []EvolutionNode{},
+	}
+	for _, detail := range link.EvolutionDetails {
+		node.Conditions = append(node.Conditions, newEvolutionCondition(detail))
+	}
+	for _, next := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, buildEvolutionNode(next))
+	}
+	return node
+}
+
+func newEvolutionCondition(d apiEvolutionDetail) EvolutionCondition {
+	c := EvolutionCondition{
+		Trigger:        d.Trigger.Name,
+		MinLevel:       d.MinLevel,
+		MinHappiness:   d.MinHappiness,
+		MinAffection:   d.MinAffection,
+		MinBeauty:      d.MinBeauty,
+		TimeOfDay:      d.TimeOfDay,
+		NeedsRain:      d.NeedsOverworldRain,
+		TurnUpsideDown: d.TurnUpsideDown,
+	}
+	name := func(r *namedResource) string {
+		if r == nil {
+			return ""
+		}
+		return titleCase(r.Name)
+	}
+	c.Item = name(d.Item)
+	c.HeldItem = name(d.HeldItem)
+	c.KnownMove = name(d.KnownMove)
+	c.KnownMoveType = name(d.KnownMoveType)
+	c.Location = name(d.Location)
+	c.PartySpecies = name(d.PartySpecies)
+	c.PartyType = name(d.PartyType)
+	c.TradeSpecies = name(d.TradeSpecies)
+	if d.Gender != nil {
+		// PokeAPI gender ids: 1 female, 2 male
+		c.Gender = map[int]string{1: "female", 2: "male"}[*d.Gender]
+	}
+	if d.RelativePhysicalStats != nil {
+		c.PhysicalStats = map[int]string{
+			1:  "attack > defense",
+			0:  "attack = defense",
+			-1: "attack < defense",
+		}[*d.RelativePhysicalStats]
+	}
+	c.Description = c.describe()
+	return c
+}
+
+// describe summarises the condition for chat, e.g. "Level up with high
+// friendship during the day"
+func (c EvolutionCondition) describe() string {
+	var parts []string
+	switch c.Trigger {
+	case "level-up":
+		if c.MinLevel != nil {
+			parts = append(parts, fmt.Sprintf("Level %d", *c.MinLevel))
+		} else {
+			parts = append(parts, "Level up")
+		}
+	case "use-item":
+		parts = append(parts, "Use "+c.Item)
+	case "trade":
+		parts = append(parts, "Trade")
+	case "shed":
+		parts = append(parts, "Level 20 with an empty party slot and a spare Poke Ball")
+	default:
+		parts = append(parts, capitalize(strings.ReplaceAll(c.Trigger, "-", " ")))
+	}
+
+	if c.MinHappiness != nil {
+		parts = append(parts, "with high friendship")
+	}
+	if c.MinAffection != nil {
+		parts = append(parts, "with high affection")
+	}
+	if c.MinBeauty != nil {
+		parts = append(parts, "with high beauty")
+	}
+	if c.KnownMove != "" {
+		parts = append(parts, "knowing "+c.KnownMove)
+	}
+	if c.KnownMoveType != "" {
+		parts = append(parts, "knowing a "+c.KnownMoveType+"-type move")
+	}
+	if c.HeldItem != "" {
+		parts = append(parts, "holding "+c.HeldItem)
+	}
+	if c.TradeSpecies != "" {
+		parts = append(parts, "for "+c.TradeSpecies)
+	}
+	if c.Location != "" {
+		parts = append(parts, "at "+c.Location)
+	}
+	switch c.TimeOfDay {
+	case "":
+	case "day":
+		parts = append(parts, "during the day")
+	case "night":
+		parts = append(parts, "at night")
+	default:
+		parts = append(parts, "at "+c.TimeOfDay)
+	}
+	if c.PartySpecies != "" {
+		parts = append(parts, "with "+c.PartySpecies+" in the party")
+	}
+	if c.PartyType != "" {
+		parts = append(parts, "with a "+c.PartyType+"-type Pokemon in the party")
+	}
+	if c.PhysicalStats != "" {
+		parts = append(parts, "with "+c.PhysicalStats)
+	}
+	if c.NeedsRain {
+		parts = append(parts, "while raining")
+	}
+	if c.TurnUpsideDown {
+		parts = append(parts, "holding the console upside down")
+	}
+	if c.Gender != "" {
+		parts = append(parts, "("+c.Gender+" only)")
+	}
+	return strings.Join(parts, " ")
+}
+
+// conditionText joins the alternative conditions of a node for use
+// mid-sentence, skipping conditions that describe nothing
+func (n *EvolutionNode) conditionText() string {
+	var descriptions []string
+	for _, c := range n.Conditions {
+		if c.Description != "" {
+			descriptions = append(descriptions, strings.ToLower(c.Description[:1])+c.Description[1:])
+		}
+	}
+	return strings.Join(descriptions, " or ")
+}
+
+// withConditions follows text with the node's conditions in parentheses,
+// when it has any
+func (n *EvolutionNode) withConditions(text string) string {
+	if conditions := n.conditionText(); conditions != "" {
+		return text + " (" + conditions + ")"
+	}
+	return text
+}
+
+// evolutionMessage describes where name sits in the tree rooted at node
+func evolutionMessage(node, parent *EvolutionNode, name string) string {
+	if !strings.EqualFold(node.Name, name) {
+		for i := range node.EvolvesTo {
+			if msg := evolutionMessage(&node.EvolvesTo[i], node, name); msg != "" {
+				return msg
+			}
+		}
+		return ""
+	}
+
+	var sentences []string
+	if parent != nil {
+		sentences = append(sentences, node.withConditions(fmt.Sprintf("%s evolves from %s", node.Name, parent.Name))+".")
+	}
+	if len(node.EvolvesTo) > 0 {
+		targets := make([]string, len(node.EvolvesTo))
+		for i, next := range node.EvolvesTo {
+			targets[i] = next.withConditions(next.Name)
+		}
+		sentences = append(sentences, fmt.Sprintf("%s evolves into %s.", node.Name, strings.Join(targets, ", ")))
+	} else if parent != nil {
+		sentences = append(sentences, fmt.Sprintf("%s is its final form.", node.Name))
+	}
+	if len(sentences) == 0 {
+		return fmt.Sprintf("%s does not evolve.", node.Name)
+	}
+	return strings.Join(sentences, " ")
+}
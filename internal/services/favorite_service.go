@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+// DefaultFavoritesLimit is how many favorites a user may keep unless
+// configured otherwise
+const DefaultFavoritesLimit = 25
+
+// ErrFavoritesFull is returned when adding past the per-user limit
+var ErrFavoritesFull = errors.New("favorites limit reached")
+
+type FavoriteService interface {
+	AddFavorite(telegramID, nameOrID string) (*FavoriteResponse, error)
+	RemoveFavorite(telegramID, nameOrID string) error
+	ListFavorites(telegramID, lang string) (*FavoritesResponse, error)
+}
+
+// FavoriteResponse is the outcome of adding a favorite. Exists is set when
+// the Pokemon already was one, which isn't an error.
+type FavoriteResponse struct {
+	Success  bool                 `json:"success"`
+	Exists   bool                 `json:"exists"`
+	Favorite *repository.Favorite `json:"favorite"`
+}
+
+// FavoritesResponse is a user's personal Pokedex: their favorites with
+// the data of each Pokemon, in the order they were added
+type FavoritesResponse struct {
+	Count     int               `json:"count"`
+	Limit     int               `json:"limit"`
+	Favorites []FavoritePokemon `json:"favorites"`
+}
+
+type FavoritePokemon struct {
+	PokemonName string       `json:"pokemon_name"`
+	AddedAt     string       `json:"added_at"`
+	Data        *PokemonData `json:"data"`
+}
+
+type favoriteService struct {
+	repo    repository.FavoriteRepository
+	users   UserService
+	pokemon PokemonService
+	limit   int
+}
+
+// NewFavoriteService stores favorites in repo, at most limit per user
+// (DefaultFavoritesLimit when limit is 0)
+func NewFavoriteService(repo repository.FavoriteRepository, users UserService, pokemon PokemonService, limit int) FavoriteService {
+	if limit <= 0 {
+		limit = DefaultFavoritesLimit
+	}
+	return &favoriteService{repo: repo, users: users, pokemon: pokemon, limit: limit}
+}
+
+// AddFavorite saves a Pokemon, given by name, id or localized name, as a
+// favorite of a registered user. The limit is checked before the insert
+// and again after it, so concurrent adds can't keep more than the limit;
+// it is best-effort all the same, as a failed recount keeps the favorite.
+func (s *favoriteService) AddFavorite(telegramID, nameOrID string) (*FavoriteResponse, error) {
+	favorites, err := s.userFavorites(telegramID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.pokemon.GetPokemonData(nameOrID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Display names are the PokeAPI name capitalized
+	name := strings.ToLower(data.Name)
+	for i := range favorites {
+		if favorites[i].PokemonName == name {
+			return &FavoriteResponse{Success: true, Exists: true, Favorite: &favorites[i]}, nil
+		}
+	}
+	if len(favorites) >= s.limit {
+		return nil, fmt.Errorf("%w: at most %d favorites", ErrFavoritesFull, s.limit)
+	}
+
+	pokemonID := data.ID
+	favorite := &repository.Favorite{TelegramID: telegramID, PokemonName: name, PokemonID: &pokemonID}
+	if err := s.repo.Add(favorite); err != nil {
+		if !errors.Is(err, repository.ErrFavoriteExists) {
+			return nil, err
+		}
+		// Lost a race with a concurrent add of the same favorite
+		return &FavoriteResponse{Success: true, Exists: true, Favorite: favorite}, nil
+	}
+	if err := s.recount(favorite); err != nil {
+		return nil, err
+	}
+	return &FavoriteResponse{Success: true, Favorite: favorite}, nil
+}
+
+// recount undoes an add that raced past the limit. Of favorites added
+// concurrently, those with the lowest ids are kept.
+func (s *favoriteService) recount(added *repository.Favorite) error {
+	favorites, err := s.repo.ListByUser(added.TelegramID)
+	if err != nil {
+		// The add succeeded; without a count it stands
+		return nil
+	}
+	older := 0
+	for _, f := range favorites {
+		if f.ID < added.ID {
+			older++
+		}
+	}
+	if older < s.limit {
+		return nil
+	}
+	if err := s.repo.Remove(added.TelegramID, added.PokemonName); err != nil && !errors.Is(err, repository.ErrFavoriteNotFound) {
+		return err
+	}
+	return fmt.Errorf("%w: at most %d favorites", ErrFavoritesFull, s.limit)
+}
+
+// RemoveFavorite drops a favorite. The Pokemon is matched by its stored
+// name first, so favorites PokeAPI no longer knows can still be removed.
+func (s *favoriteService) RemoveFavorite(telegramID, nameOrID string) error {
+	if _, err := s.users.GetUserByTelegramID(telegramID); err != nil {
+		return err
+	}
+
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(nameOrID)), " ", "-")
+	err := s.repo.Remove(telegramID, name)
+	if !errors.Is(err, repository.ErrFavoriteNotFound) {
+		return err
+	}
+
+	// Ids and localized names are stored under the PokeAPI name
+	data, lookupErr := s.pokemon.GetPokemonData(nameOrID, "")
+	if lookupErr != nil || strings.ToLower(data.Name) == name {
+		return err
+	}
+	return s.repo.Remove(telegramID, strings.ToLower(data.Name))
+}
+
+// ListFavorites returns a user's favorites with each Pokemon's data in
+// lang. Pokemon are looked up concurrently, like a page of SearchByPrefix.
+func (s *favoriteService) ListFavorites(telegramID, lang string) (*FavoritesResponse, error) {
+	favorites, err := s.userFavorites(telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]FavoritePokemon, len(favorites))
+	errs := make([]error, len(favorites))
+	sem := make(chan struct{}, prefixWorkers)
+	var wg sync.WaitGroup
+	for i, f := range favorites {
+		entries[i] = FavoritePokemon{PokemonName: f.PokemonName, AddedAt: f.CreatedAt}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			entries[i].Data, errs[i] = s.pokemon.GetPokemonData(name, lang)
+		}(i, f.PokemonName)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		// A favorite PokeAPI no longer knows is listed without data
+		if err != nil && !errors.Is(err, ErrPokemonNotFound) {
+			return nil, err
+		}
+	}
+	return &FavoritesResponse{Count: len(entries), Limit: s.limit, Favorites: entries}, nil
+}
+
+// userFavorites returns the favorites of a registered user
+func (s *favoriteService) userFavorites(telegramID string) ([]repository.Favorite, error) {
+	if _, err := s.users.GetUserByTelegramID(telegramID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByUser(telegramID)
+}
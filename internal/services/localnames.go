@@ -0,0 +1,157 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokedex"
+)
+
+// localName is an entry of a PokeAPI names array
+type localName struct {
+	Name     string        `json:"name"`
+	Language namedResource `json:"language"`
+}
+
+// pickName returns the name in lang, "" when PokeAPI has none
+func pickName(names []localName, lang string) string {
+	for _, n := range names {
+		if strings.EqualFold(n.Language.Name, lang) {
+			return n.Name
+		}
+	}
+	return ""
+}
+
+// localizes reports whether names should be translated into lang
+func localizes(lang string) bool {
+	return lang != "" && lang != i18n.Default && i18n.HasNames(lang)
+}
+
+// typeName returns the name of a type in lang, fetching the type resource
+// when it isn't English
+func (s *pokemonService) typeName(slug, lang string) string {
+	if localizes(lang) {
+		if body, err := s.fetch("type", slug); err == nil {
+			var t struct {
+				Names []localName `json:"names"`
+			}
+			if json.Unmarshal(body, &t) == nil {
+				if name := pickName(t.Names, lang); name != "" {
+					return name
+				}
+			}
+		}
+	}
+	return capitalize(slug)
+}
+
+// nameAliases maps the localized names of species ("Évoli", "ピカチュウ")
+// to the PokeAPI name of their default Pokemon. It learns from every
+// species fetched, from all species of the Pokedex snapshot once needed,
+// and from all of PokeAPI's with LoadNameAliases.
+type nameAliases struct {
+	mu     sync.RWMutex
+	names  map[string]string
+	seeded sync.Once
+}
+
+func newNameAliases() *nameAliases {
+	return &nameAliases{names: make(map[string]string)}
+}
+
+func aliasKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// add records every localized name of a species
+func (a *nameAliases) add(sp *apiSpecies) {
+	target := sp.Name
+	for _, v := range sp.Varieties {
+		if v.IsDefault {
+			target = v.Pokemon.Name
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, n := range sp.Names {
+		if key := aliasKey(n.Name); key != "" && key != target {
+			a.names[key] = target
+		}
+	}
+}
+
+// lookup returns the Pokemon a localized name refers to
+func (a *nameAliases) lookup(name string, store *pokedex.Store) (string, bool) {
+	if store != nil {
+		a.seeded.Do(func() { a.seed(store) })
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	target, ok := a.names[aliasKey(name)]
+	return target, ok
+}
+
+func (a *nameAliases) seed(store *pokedex.Store) {
+	for _, name := range store.Names(pokedex.Species) {
+		body, ok := store.Get(pokedex.Species, name)
+		if !ok {
+			continue
+		}
+		var sp apiSpecies
+		if json.Unmarshal(body, &sp) == nil {
+			a.add(&sp)
+		}
+	}
+}
+
+// LoadNameAliases learns the localized names of every species, so they
+// resolve before anything fetched their species. It reads the Pokedex
+// snapshot when it has species, and otherwise fetches each species from
+// PokeAPI, through the cache, returning how many it learned. A species
+// that fails to load is skipped and the last such error returned.
+func (s *pokemonService) LoadNameAliases() (int, error) {
+	if s.pokedex != nil && s.pokedex.Count(pokedex.Species) > 0 {
+		s.aliases.seeded.Do(func() { s.aliases.seed(s.pokedex) })
+		return s.pokedex.Count(pokedex.Species), nil
+	}
+	if s.offline {
+		return 0, nil
+	}
+
+	names, err := s.listResource("pokemon-species")
+	if err != nil {
+		return 0, err
+	}
+	var (
+		mu      sync.Mutex
+		loaded  int
+		lastErr error
+	)
+	sem := make(chan struct{}, prefixWorkers)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// fetchSpecies adds the aliases
+			_, err := s.fetchSpecies(name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = fmt.Errorf("species %s: %w", name, err)
+				return
+			}
+			loaded++
+		}(name)
+	}
+	wg.Wait()
+	return loaded, lastErr
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+)
+
+type MatchupResponse struct {
+	Found   bool         `json:"found"`
+	Message string       `json:"message"`
+	Data    *MatchupData `json:"data,omitempty"`
+}
+
+// MatchupData lists how every attacking type fares against a Pokemon.
+// Generation is set when a generation-specific chart was asked for.
+type MatchupData struct {
+	ID          int                 `json:"id"`
+	Name        string              `json:"name"`
+	Types       []string            `json:"types"`
+	Generation  int                 `json:"generation,omitempty"`
+	Weaknesses  []typechart.Matchup `json:"weaknesses"`
+	Resistances []typechart.Matchup `json:"resistances"`
+	Immunities  []typechart.Matchup `json:"immunities"`
+}
+
+type TypeResponse struct {
+	Found   bool                 `json:"found"`
+	Message string               `json:"message"`
+	Data    *typechart.Relations `json:"data,omitempty"`
+}
+
+// apiPokemonTypes is the typing of a pokemon resource. past_types lists
+// the types a Pokemon had up to and including an older generation.
+type apiPokemonTypes struct {
+	ID        int          `json:"id"`
+	Name      string       `json:"name"`
+	Types     []apiTypeRef `json:"types"`
+	PastTypes []struct {
+		Generation namedResource `json:"generation"`
+		Types      []apiTypeRef  `json:"types"`
+	} `json:"past_types"`
+}
+
+type apiTypeRef struct {
+	Slot int           `json:"slot"`
+	Type namedResource `json:"type"`
+}
+
+// typesIn returns the Pokemon's types in a generation, 0 meaning current
+func (p *apiPokemonTypes) typesIn(generation int) []string {
+	refs := p.Types
+	if generation > 0 {
+		// The closest past entry at or after the generation applies
+		best := 0
+		for _, past := range p.PastTypes {
+			gen := generationNumber(past.Generation.Name)
+			if gen >= generation && (best == 0 || gen < best) {
+				best, refs = gen, past.Types
+			}
+		}
+	}
+
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Type.Name
+	}
+	return names
+}
+
+// GetMatchups returns the weaknesses, resistances and immunities of a
+// Pokemon using the chart of a generation, 0 meaning the current one
+func (s *pokemonService) GetMatchups(nameOrID string, generation int) (*MatchupResponse, error) {
+	chart, err := typechart.ForGeneration(generation)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.fetch("pokemon", nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		return &MatchupResponse{
+			Found:   false,
+			Message: fmt.Sprintf("Sorry we don't have information for <%s>", nameOrID),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pokemon apiPokemonTypes
+	if err := json.Unmarshal(body, &pokemon); err != nil {
+		return nil, err
+	}
+
+	types := pokemon.typesIn(generation)
+	matchups := chart.Defending(types...)
+	data := &MatchupData{
+		ID:          pokemon.ID,
+		Name:        capitalize(pokemon.Name),
+		Types:       types,
+		Generation:  generation,
+		Weaknesses:  matchups.Weaknesses,
+		Resistances: matchups.Resistances,
+		Immunities:  matchups.Immunities,
+	}
+
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = capitalize(t)
+	}
+	message := fmt.Sprintf("%s (%s)", data.Name, strings.Join(typeNames, "/"))
+	if len(matchups.Weaknesses) > 0 {
+		message += " is weak to " + formatMatchups(matchups.Weaknesses)
+	} else {
+		message += " has no weaknesses"
+	}
+	if len(matchups.Immunities) > 0 {
+		immune := make([]string, len(matchups.Immunities))
+		for i, m := range matchups.Immunities {
+			immune[i] = m.Type
+		}
+		message += " and immune to " + formatTypes(immune)
+	}
+
+	return &MatchupResponse{
+		Found:   true,
+		Message: message + ".",
+		Data:    data,
+	}, nil
+}
+
+// GetType returns how a type fares attacking and defending in the chart
+// of a generation, 0 meaning the current one
+func (s *pokemonService) GetType(name string, generation int) (*TypeResponse, error) {
+	chart, err := typechart.ForGeneration(generation)
+	if err != nil {
+		return nil, err
+	}
+
+	relations, ok := chart.Relations(name)
+	if !ok {
+		return &TypeResponse{
+			Found:   false,
+			Message: fmt.Sprintf("Sorry we don't have information for type <%s>", name),
+		}, nil
+	}
+
+	title := capitalize(relations.Type)
+	message := fmt.Sprintf("%s moves are super effective against %s.", title, formatTypes(relations.Attacking.SuperEffective))
+	if len(relations.Defending.Weaknesses) > 0 {
+		message += fmt.Sprintf(" %s Pokemon are weak to %s.", title, formatMatchups(relations.Defending.Weaknesses))
+	}
+
+	return &TypeResponse{
+		Found:   true,
+		Message: message,
+		Data:    &relations,
+	}, nil
+}
+
+// weaknessSummary lists the current-chart weaknesses of the given types,
+// e.g. "Rock (4x), Water (2x), Electric (2x)"
+func weaknessSummary(types []string) string {
+	return formatMatchups(typechart.Latest().Defending(types...).Weaknesses)
+}
+
+func formatMatchups(matchups []typechart.Matchup) string {
+	parts := make([]string, len(matchups))
+	for i, m := range matchups {
+		parts[i] = fmt.Sprintf("%s (%sx)", capitalize(m.Type), strconv.FormatFloat(m.Multiplier, 'f', -1, 64))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatTypes(types []string) string {
+	if len(types) == 0 {
+		return "nothing"
+	}
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = capitalize(t)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+)
+
+// MessageOptions selects how reply messages are written: the i18n language
+// and the templates channel ("" for each default)
+type MessageOptions struct {
+	Lang    string
+	Channel string
+}
+
+func (o MessageOptions) channel() string {
+	if o.Channel == "" {
+		return templates.Plain
+	}
+	return o.Channel
+}
+
+// notFoundData is what the pokemon.not_found template sees
+type notFoundData struct {
+	Query string
+}
+
+// WithTemplates writes reply messages with set instead of the built-in
+// templates
+func WithTemplates(set *templates.Set) PokemonOption {
+	return func(s *pokemonService) {
+		s.templates = set
+	}
+}
+
+// pokemonMessage renders the pokemon.found message of data
+func (s *pokemonService) pokemonMessage(data *PokemonData, opts MessageOptions) (string, error) {
+	return s.templates.Render(opts.channel(), templates.PokemonFound, opts.Lang, data)
+}
+
+// notFoundMessage renders the pokemon.not_found message for a query
+func (s *pokemonService) notFoundMessage(query string, opts MessageOptions) (string, error) {
+	return s.templates.Render(opts.channel(), templates.PokemonNotFound, opts.Lang, notFoundData{Query: query})
+}
+
+// PreviewMessage renders the message a lookup of nameOrID would get in a
+// channel. A non-empty source replaces the channel's template for this
+// preview only; parse errors wrap templates.ErrInvalidTemplate. Previews
+// aren't logged as searches.
+func (s *pokemonService) PreviewMessage(nameOrID string, opts MessageOptions, source string) (*PokemonResponse, error) {
+	render := func(message string, data interface{}) (string, error) {
+		return s.templates.Render(opts.channel(), message, opts.Lang, data)
+	}
+	if source != "" {
+		tmpl, err := templates.Parse(opts.channel(), source)
+		if err != nil {
+			return nil, err
+		}
+		render = func(message string, data interface{}) (string, error) {
+			return templates.Execute(tmpl, message, opts.Lang, data)
+		}
+	}
+
+	data, _, err := s.lookupPokemon(nameOrID, opts.Lang, false)
+	if errors.Is(err, errResourceNotFound) {
+		message, err := render(templates.PokemonNotFound, notFoundData{Query: nameOrID})
+		if err != nil {
+			return nil, invalidTemplate(err)
+		}
+		return &PokemonResponse{Found: false, Message: message}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := render(templates.PokemonFound, data)
+	if err != nil {
+		return nil, invalidTemplate(err)
+	}
+	return &PokemonResponse{Found: true, Message: message, Data: data}, nil
+}
+
+// invalidTemplate marks an execution error as the template's fault, e.g. a
+// field PokemonData doesn't have
+func invalidTemplate(err error) error {
+	if errors.Is(err, templates.ErrInvalidTemplate) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", templates.ErrInvalidTemplate, err)
+}
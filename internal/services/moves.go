@@ -0,0 +1,365 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LearnMethods are the move learn methods the moves endpoint filters on
+var LearnMethods = []string{"level-up", "machine", "egg", "tutor"}
+
+// Sort orders of a move list
+const (
+	MoveSortLevel = "level"
+	MoveSortName  = "name"
+)
+
+// MoveFilter narrows a Pokemon's move list. An empty VersionGroup means the
+// newest version group the Pokemon has moves in; an empty Method means all.
+type MoveFilter struct {
+	VersionGroup string
+	Method       string
+	Sort         string
+}
+
+type MovesResponse struct {
+	Found   bool       `json:"found"`
+	Message string     `json:"message"`
+	Data    *MovesData `json:"data,omitempty"`
+}
+
+type MovesData struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	VersionGroup string        `json:"version_group"`
+	Method       string        `json:"method,omitempty"`
+	Moves        []LearnedMove `json:"moves"`
+}
+
+// LearnedMove is one way a Pokemon learns a move. Level is only set for
+// level-up moves; 0 there means on evolution.
+type LearnedMove struct {
+	Name   string `json:"name"`
+	Move   string `json:"move"`
+	Method string `json:"method"`
+	Level  *int   `json:"level,omitempty"`
+}
+
+type MoveResponse struct {
+	Found   bool      `json:"found"`
+	Message string    `json:"message"`
+	Data    *MoveData `json:"data,omitempty"`
+}
+
+// MoveData describes a move. Power and accuracy are null for moves that
+// deal no direct damage or never miss.
+type MoveData struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	DamageClass  string `json:"damage_class"`
+	Power        *int   `json:"power"`
+	Accuracy     *int   `json:"accuracy"`
+	PP           int    `json:"pp"`
+	Priority     int    `json:"priority"`
+	EffectChance *int   `json:"effect_chance,omitempty"`
+	Effect       string `json:"effect"`
+	FlavorText   string `json:"flavor_text,omitempty"`
+	Target       string `json:"target"`
+	Generation   int    `json:"generation"`
+}
+
+// apiPokemonMoves is the move list of a pokemon resource
+type apiPokemonMoves struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Moves []struct {
+		Move                namedResource `json:"move"`
+		VersionGroupDetails []struct {
+			LevelLearnedAt  int           `json:"level_learned_at"`
+			MoveLearnMethod namedResource `json:"move_learn_method"`
+			VersionGroup    namedResource `json:"version_group"`
+		} `json:"version_group_details"`
+	} `json:"moves"`
+}
+
+// apiMove is the part of a move resource the service reads
+type apiMove struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	Accuracy      *int          `json:"accuracy"`
+	Power         *int          `json:"power"`
+	PP            int           `json:"pp"`
+	Priority      int           `json:"priority"`
+	EffectChance  *int          `json:"effect_chance"`
+	Type          namedResource `json:"type"`
+	DamageClass   namedResource `json:"damage_class"`
+	Target        namedResource `json:"target"`
+	Generation    namedResource `json:"generation"`
+	Names         []localName   `json:"names"`
+	EffectEntries []struct {
+		ShortEffect string        `json:"short_effect"`
+		Language    namedResource `json:"language"`
+	} `json:"effect_entries"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   namedResource `json:"language"`
+	} `json:"flavor_text_entries"`
+}
+
+// GetMoves lists the moves a Pokemon learns in one version group
+func (s *pokemonService) GetMoves(nameOrID string, filter MoveFilter) (*MovesResponse, error) {
+	body, err := s.fetchPokemon(nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		return &MovesResponse{
+			Found:   false,
+			Message: fmt.Sprintf("Sorry we don't have information for <%s>", nameOrID),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pokemon apiPokemonMoves
+	if err := json.Unmarshal(body, &pokemon); err != nil {
+		return nil, err
+	}
+
+	versionGroup := strings.ToLower(filter.VersionGroup)
+	if versionGroup == "" {
+		versionGroup = pokemon.latestVersionGroup()
+	}
+
+	moves := []LearnedMove{}
+	for _, m := range pokemon.Moves {
+		for _, d := range m.VersionGroupDetails {
+			method := d.MoveLearnMethod.Name
+			if d.VersionGroup.Name != versionGroup || (filter.Method != "" && method != filter.Method) {
+				continue
+			}
+			learned := LearnedMove{Name: titleCase(m.Move.Name), Move: m.Move.Name, Method: method}
+			if method == "level-up" {
+				level := d.LevelLearnedAt
+				learned.Level = &level
+			}
+			moves = append(moves, learned)
+		}
+	}
+	sortMoves(moves, filter.Sort)
+
+	data := &MovesData{
+		ID:           pokemon.ID,
+		Name:         capitalize(pokemon.Name),
+		VersionGroup: versionGroup,
+		Method:       filter.Method,
+		Moves:        moves,
+	}
+	return &MovesResponse{
+		Found:   true,
+		Message: movesMessage(data),
+		Data:    data,
+	}, nil
+}
+
+// latestVersionGroup returns the newest version group in the move list.
+// Version group ids grow with each release.
+func (p *apiPokemonMoves) latestVersionGroup() string {
+	latest, latestID := "", -1
+	for _, m := range p.Moves {
+		for _, d := range m.VersionGroupDetails {
+			if id, _ := strconv.Atoi(d.VersionGroup.id()); id > latestID {
+				latest, latestID = d.VersionGroup.Name, id
+			}
+		}
+	}
+	return latest
+}
+
+// sortMoves orders level-up moves by level, then the other methods in
+// LearnMethods order, each by name; or everything by name
+func sortMoves(moves []LearnedMove, order string) {
+	rank := make(map[string]int, len(LearnMethods))
+	for i, m := range LearnMethods {
+		rank[m] = i
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		a, b := moves[i], moves[j]
+		if order != MoveSortName {
+			if rank[a.Method] != rank[b.Method] {
+				return rank[a.Method] < rank[b.Method]
+			}
+			if a.Level != nil && b.Level != nil && *a.Level != *b.Level {
+				return *a.Level < *b.Level
+			}
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return rank[a.Method] < rank[b.Method]
+	})
+}
+
+// learnPhrases describe how moves of each method are learned
+var learnPhrases = map[string]string{
+	"level-up": "by level up",
+	"machine":  "from TMs",
+	"egg":      "as egg moves",
+	"tutor":    "from move tutors",
+}
+
+func movesMessage(data *MovesData) string {
+	game := titleCase(data.VersionGroup)
+	if len(data.Moves) == 0 {
+		if data.Method != "" {
+			return fmt.Sprintf("%s learns no moves %s in %s.", data.Name, learnPhrases[data.Method], game)
+		}
+		return fmt.Sprintf("%s learns no moves in %s.", data.Name, game)
+	}
+
+	if data.Method != "" {
+		names := make([]string, len(data.Moves))
+		for i, m := range data.Moves {
+			names[i] = m.Name
+			if m.Level != nil && *m.Level > 0 {
+				names[i] += fmt.Sprintf(" (Lv. %d)", *m.Level)
+			} else if m.Level != nil {
+				names[i] += " (on evolution)"
+			}
+		}
+		return fmt.Sprintf("%s learns %s %s in %s: %s.",
+			data.Name, countMoves(len(data.Moves)), learnPhrases[data.Method], game, strings.Join(names, ", "))
+	}
+
+	counts := make(map[string]int)
+	for _, m := range data.Moves {
+		counts[m.Method]++
+	}
+	var parts []string
+	for _, method := range LearnMethods {
+		if counts[method] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[method], learnPhrases[method]))
+		}
+	}
+	return fmt.Sprintf("%s learns %s in %s: %s.", data.Name, countMoves(len(data.Moves)), game, joinAnd(parts))
+}
+
+func countMoves(n int) string {
+	if n == 1 {
+		return "1 move"
+	}
+	return fmt.Sprintf("%d moves", n)
+}
+
+// GetMove returns the details of a move by name ("thunderbolt", "Thunder
+// Punch") or id
+func (s *pokemonService) GetMove(nameOrID string) (*MoveResponse, error) {
+	slug := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(nameOrID)), " ", "-")
+	body, err := s.fetch("move", slug)
+	if errors.Is(err, errResourceNotFound) {
+		return &MoveResponse{
+			Found:   false,
+			Message: fmt.Sprintf("Sorry we don't have information for move <%s>", nameOrID),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var move apiMove
+	if err := json.Unmarshal(body, &move); err != nil {
+		return nil, err
+	}
+
+	data := &MoveData{
+		ID:           move.ID,
+		Name:         move.displayName(),
+		Type:         move.Type.Name,
+		DamageClass:  move.DamageClass.Name,
+		Power:        move.Power,
+		Accuracy:     move.Accuracy,
+		PP:           move.PP,
+		Priority:     move.Priority,
+		EffectChance: move.EffectChance,
+		Effect:       move.effect(),
+		FlavorText:   move.flavorText(),
+		Target:       move.Target.Name,
+		Generation:   generationNumber(move.Generation.Name),
+	}
+	return &MoveResponse{
+		Found:   true,
+		Message: moveMessage(data),
+		Data:    data,
+	}, nil
+}
+
+func (m *apiMove) displayName() string {
+	if name := pickName(m.Names, "en"); name != "" {
+		return name
+	}
+	return titleCase(m.Name)
+}
+
+// effect returns the English short effect with the effect chance filled in
+func (m *apiMove) effect() string {
+	for _, e := range m.EffectEntries {
+		if e.Language.Name != "en" {
+			continue
+		}
+		if m.EffectChance != nil {
+			return strings.ReplaceAll(e.ShortEffect, "$effect_chance", strconv.Itoa(*m.EffectChance))
+		}
+		return e.ShortEffect
+	}
+	return ""
+}
+
+// flavorText returns the most recent English description on one line
+func (m *apiMove) flavorText() string {
+	text := ""
+	for _, entry := range m.FlavorTextEntries {
+		if entry.Language.Name == "en" {
+			text = entry.FlavorText
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// moveMessage summarises a move, e.g. "Thunderbolt is an Electric-type
+// special move with 90 power, 100% accuracy and 15 PP."
+func moveMessage(m *MoveData) string {
+	var stats []string
+	if m.Power != nil {
+		stats = append(stats, fmt.Sprintf("%d power", *m.Power))
+	}
+	if m.Accuracy != nil {
+		stats = append(stats, fmt.Sprintf("%d%% accuracy", *m.Accuracy))
+	}
+	stats = append(stats, fmt.Sprintf("%d PP", m.PP))
+
+	// Partial snapshots and fixtures may lack the type or damage class
+	var kind []string
+	if m.Type != "" {
+		kind = append(kind, capitalize(m.Type)+"-type")
+	}
+	if m.DamageClass != "" {
+		kind = append(kind, m.DamageClass)
+	}
+	kind = append(kind, "move")
+	article := "a"
+	if strings.ContainsAny(strings.ToLower(kind[0][:1]), "aeiou") {
+		article = "an"
+	}
+	message := fmt.Sprintf("%s is %s %s with %s", m.Name, article, strings.Join(kind, " "), joinAnd(stats))
+	if m.Priority != 0 {
+		message += fmt.Sprintf(" (priority %+d)", m.Priority)
+	}
+	message += "."
+	if m.Effect != "" {
+		message += " " + m.Effect
+	}
+	return message
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/nlu"
+)
+
+// minFuzzyLength is the shortest word matched to a Pokemon despite typos;
+// shorter words are too close to everyday ones ("new" and Mew)
+const minFuzzyLength = 5
+
+// ParseMessage reads the intent of a chat message and the Pokemon and
+// types it mentions
+func (s *pokemonService) ParseMessage(text, lang string) (*nlu.Result, error) {
+	names, err := s.pokemonNames()
+	if err != nil {
+		return nil, err
+	}
+	return nlu.Parse(text, lang, func(phrase string) (string, float64, bool) {
+		return s.resolvePokemon(names, phrase)
+	}), nil
+}
+
+// resolvePokemon finds the Pokemon a phrase names: exactly, by a localized
+// name, or with about one typo per four letters
+func (s *pokemonService) resolvePokemon(names []string, phrase string) (string, float64, bool) {
+	slug := strings.ReplaceAll(phrase, " ", "-")
+	if i := sort.SearchStrings(names, slug); i < len(names) && names[i] == slug {
+		return slug, 1, true
+	}
+	if target, ok := s.aliases.lookup(phrase, s.pokedex); ok {
+		return target, 1, true
+	}
+
+	length := len([]rune(slug))
+	if length < minFuzzyLength {
+		return "", 0, false
+	}
+	maxDistance := max(1, length/4)
+	best, bestDistance := "", maxDistance+1
+	for _, name := range names {
+		if diff := len([]rune(name)) - length; diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		if d := editDistance(slug, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return "", 0, false
+	}
+	return best, 1 - float64(bestDistance)/float64(length), true
+}
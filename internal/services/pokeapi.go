@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// errResourceNotFound is returned by fetch when PokeAPI answers 404
+var errResourceNotFound = errors.New("resource not found")
+
+// fetch returns the raw JSON of a PokeAPI resource such as ("pokemon", "25"),
+// serving it from the local Pokedex or the cache when possible. Only
+// successful responses are cached.
+func (s *pokemonService) fetch(resource, nameOrID string) ([]byte, error) {
+	if s.pokedex != nil {
+		if body, ok := s.pokedex.Get(resource, nameOrID); ok {
+			return body, nil
+		}
+	}
+	if s.offline {
+		return nil, errResourceNotFound
+	}
+
+	// An empty name would fetch the resource list
+	if nameOrID == "" {
+		return nil, errResourceNotFound
+	}
+	key := resource + "/" + strings.ToLower(nameOrID)
+	if s.cache != nil {
+		if body, ok := s.cache.Get(key); ok {
+			return body, nil
+		}
+	}
+
+	// Names come from users; escaping keeps "?limit=1" or "../type" from
+	// fetching some other document
+	resp, err := s.client.Get(fmt.Sprintf("%s/%s/%s", s.baseURL, resource, url.PathEscape(strings.ToLower(nameOrID))))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errResourceNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PokeAPI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		s.cache.Set(key, body)
+	}
+	return body, nil
+}
+
+// WarmCache fetches a Pokemon into the cache without logging a search
+func (s *pokemonService) WarmCache(nameOrID string) error {
+	_, err := s.fetch("pokemon", nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		return fmt.Errorf("pokemon %q not found", nameOrID)
+	}
+	return err
+}
@@ -2,29 +2,113 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/yourusername/pokemon-chatbot-api/internal/nlu"
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokedex"
 	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
 )
 
 type PokemonService interface {
-	GetPokemon(nameOrID string) (*PokemonResponse, error)
-	GetSearchStats() (*repository.SearchStats, error)
+	GetPokemon(nameOrID string, opts MessageOptions) (*PokemonResponse, error)
+	GetPokemonV2(nameOrID string, opts MessageOptions) (*PokemonV2Response, error)
+	GetPokemonData(nameOrID, lang string) (*PokemonData, error)
+	PreviewMessage(nameOrID string, opts MessageOptions, source string) (*PokemonResponse, error)
+	SuggestPokemon(query string, limit int) ([]PokemonSuggestion, error)
+	SearchByPrefix(prefix string, offset, limit int, opts MessageOptions) (*PrefixSearch, error)
+	ParseMessage(text, lang string) (*nlu.Result, error)
+	GetSearchStats(window repository.TimeWindow) (*repository.SearchStats, error)
+	ListSearches(cursor string, limit int) ([]repository.PokemonSearch, string, error)
+	GetEvolution(nameOrID string) (*EvolutionResponse, error)
+	GetMatchups(nameOrID string, generation int) (*MatchupResponse, error)
+	GetType(name string, generation int) (*TypeResponse, error)
+	ComparePokemon(names []string) (*CompareResponse, error)
+	GetMoves(nameOrID string, filter MoveFilter) (*MovesResponse, error)
+	GetMove(nameOrID string) (*MoveResponse, error)
+	GetAbility(nameOrID string) (*AbilityResponse, error)
+	CheckTeamMember(member repository.TeamMember) (repository.TeamMember, error)
+	AnalyzeTeam(members []repository.TeamMember, generation int) (*TeamAnalysis, error)
+	WarmCache(nameOrID string) error
+	LoadNameAliases() (int, error)
 }
 
 type pokemonService struct {
 	client     *http.Client
 	baseURL    string
 	searchRepo repository.SearchRepository
+	cache      Cache
+	pokedex    *pokedex.Store
+	offline    bool
+	aliases    *nameAliases
+	templates  *templates.Set
+	names      nameIndex
+	favorites  repository.FavoriteRepository
+}
+
+// defaultPokeAPIURL is the public PokeAPI v2 endpoint
+const defaultPokeAPIURL = "https://pokeapi.co/api/v2"
+
+// ErrPokemonNotFound is returned for names and ids PokeAPI doesn't know
+var ErrPokemonNotFound = errors.New("pokemon not found")
+
+// PokemonOption configures optional pokemonService dependencies
+type PokemonOption func(*pokemonService)
+
+// WithCache caches PokeAPI responses in c
+func WithCache(c Cache) PokemonOption {
+	return func(s *pokemonService) {
+		s.cache = c
+	}
+}
+
+// WithBaseURL points the service at another PokeAPI v2 endpoint, such as a
+// mirror or the pokeapi-fake server. An empty url keeps the default.
+func WithBaseURL(url string) PokemonOption {
+	return func(s *pokemonService) {
+		if url != "" {
+			s.baseURL = strings.TrimSuffix(url, "/")
+		}
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used to call PokeAPI
+func WithHTTPClient(client *http.Client) PokemonOption {
+	return func(s *pokemonService) {
+		s.client = client
+	}
+}
+
+// WithPokedex serves resources from a local snapshot first. Entries missing
+// from it are fetched from PokeAPI, unless offline is set, in which case
+// they are reported as not found.
+func WithPokedex(store *pokedex.Store, offline bool) PokemonOption {
+	return func(s *pokemonService) {
+		s.pokedex = store
+		s.offline = offline
+	}
+}
+
+// WithFavorites adds favorite counts to the search stats
+func WithFavorites(repo repository.FavoriteRepository) PokemonOption {
+	return func(s *pokemonService) {
+		s.favorites = repo
+	}
 }
 
 type PokemonResponse struct {
 	Found   bool         `json:"found"`
 	Message string       `json:"message"`
 	Data    *PokemonData `json:"data,omitempty"`
+	// Suggestions are close names when nothing was found
+	Suggestions []PokemonSuggestion `json:"suggestions,omitempty"`
 }
 
 type PokemonData struct {
@@ -36,6 +120,18 @@ type PokemonData struct {
 	Height    string       `json:"height"`
 	Weight    string       `json:"weight"`
 	Sprite    string       `json:"sprite"`
+	// Weaknesses summarises the types that hit for more than 1x
+	Weaknesses string `json:"weaknesses,omitempty"`
+	// AbilityDetails is Abilities with hidden flags, slots and effects
+	AbilityDetails []PokemonAbility `json:"abilityDetails,omitempty"`
+
+	// From the species; left empty when the species can't be fetched
+	Genus       string `json:"genus,omitempty"`
+	FlavorText  string `json:"flavorText,omitempty"`
+	Generation  int    `json:"generation,omitempty"`
+	IsLegendary bool   `json:"isLegendary,omitempty"`
+	IsMythical  bool   `json:"isMythical,omitempty"`
+	CaptureRate int    `json:"captureRate,omitempty"`
 }
 
 type PokemonStats struct {
@@ -47,119 +143,304 @@ type PokemonStats struct {
 	Speed     int `json:"speed"`
 }
 
-func NewPokemonService(searchRepo repository.SearchRepository) PokemonService {
-	return &pokemonService{
+// Total returns the base stat total
+func (st PokemonStats) Total() int {
+	return st.HP + st.Attack + st.Defense + st.SpAttack + st.SpDefense + st.Speed
+}
+
+func NewPokemonService(searchRepo repository.SearchRepository, opts ...PokemonOption) PokemonService {
+	s := &pokemonService{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		baseURL:    "https://pokeapi.co/api/v2",
+		baseURL:    defaultPokeAPIURL,
 		searchRepo: searchRepo,
+		aliases:    newNameAliases(),
 	}
-}
-
-func (s *pokemonService) GetPokemon(nameOrID string) (*PokemonResponse, error) {
-	url := fmt.Sprintf("%s/pokemon/%s", s.baseURL, strings.ToLower(nameOrID))
-
-	resp, err := s.client.Get(url)
-	if err != nil {
-		return nil, err
+	for _, opt := range opts {
+		opt(s)
 	}
-	defer resp.Body.Close()
+	if s.templates == nil {
+		s.templates = templates.Default()
+	}
+	return s
+}
 
-	if resp.StatusCode == 404 {
-		// Log not found search
-		if s.searchRepo != nil {
-			s.searchRepo.LogSearch(nameOrID, nil, false)
+// GetPokemon looks a Pokemon up by name, id or localized name and answers
+// in the language and channel format of opts
+func (s *pokemonService) GetPokemon(nameOrID string, opts MessageOptions) (*PokemonResponse, error) {
+	data, _, err := s.lookupPokemon(nameOrID, opts.Lang, true)
+	if errors.Is(err, errResourceNotFound) {
+		message, err := s.notFoundMessage(nameOrID, opts)
+		if err != nil {
+			return nil, err
 		}
 		return &PokemonResponse{
 			Found:   false,
-			Message: fmt.Sprintf("Sorry we don't have information for <%s>", nameOrID),
+			Message: message,
 			Data: &PokemonData{
 				Name:   "Not Found",
 				Types:  "Unknown",
 				Sprite: "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/0.png",
 			},
+			Suggestions: s.suggest(nameOrID),
 		}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.pokemonMessage(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PokemonResponse{
+		Found:   true,
+		Message: message,
+		Data:    data,
+	}, nil
+}
+
+// GetPokemonData looks a Pokemon up like GetPokemon, with names in lang,
+// but without a message and without logging the search. An unknown name is
+// ErrPokemonNotFound.
+func (s *pokemonService) GetPokemonData(nameOrID, lang string) (*PokemonData, error) {
+	data, _, err := s.lookupPokemon(nameOrID, lang, false)
+	if errors.Is(err, errResourceNotFound) {
+		return nil, ErrPokemonNotFound
+	}
+	return data, err
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("PokeAPI returned status %d", resp.StatusCode)
+// lookupPokemon fetches a Pokemon with its species details and ability
+// effects, with names in lang, logging the search when logSearch is set.
+// Localized names such as "Évoli" are resolved through the species seen so
+// far. The raw resource is returned too for callers that need more of it.
+func (s *pokemonService) lookupPokemon(nameOrID, lang string, logSearch bool) (*PokemonData, []byte, error) {
+	body, err := s.fetchPokemon(nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		// Log not found search
+		if logSearch && s.searchRepo != nil {
+			s.searchRepo.LogSearch(nameOrID, nil, false)
+		}
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var rawData map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
-		return nil, err
+	if err := json.Unmarshal(body, &rawData); err != nil {
+		return nil, nil, err
 	}
 
-	data := s.transformData(rawData)
-	message := fmt.Sprintf("%s is an <%s> type Pokemon with %s weight and %s height, here's a picture of %s.",
-		data.Name, data.Types, data.Weight, data.Height, data.Name)
+	data, err := s.transformData(rawData)
+	if err != nil {
+		return nil, nil, err
+	}
+	var species *apiSpecies
+	if name := nestedName(rawData, "species"); name != "" {
+		// Species details are extras; the lookup succeeds without them
+		if sp, err := s.fetchSpecies(name); err == nil {
+			species = sp
+			data.applySpecies(sp)
+		}
+	}
+	s.describeAbilities(data.AbilityDetails, lang)
 
-	// Log successful search
-	if s.searchRepo != nil {
+	// Log successful search, under the English name
+	if logSearch && s.searchRepo != nil {
 		pokemonID := data.ID
 		s.searchRepo.LogSearch(data.Name, &pokemonID, true)
 	}
 
-	return &PokemonResponse{
-		Found:   true,
-		Message: message,
-		Data:    data,
-	}, nil
+	if localizes(lang) {
+		s.localize(data, species, lang)
+	}
+	return data, body, nil
+}
+
+// fetchPokemon fetches a pokemon resource by name, id or localized name
+func (s *pokemonService) fetchPokemon(nameOrID string) ([]byte, error) {
+	body, err := s.fetch("pokemon", nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		if target, ok := s.aliases.lookup(nameOrID, s.pokedex); ok {
+			body, err = s.fetch("pokemon", target)
+		}
+	}
+	return body, err
+}
+
+// localize replaces the display names of a Pokemon with those in lang,
+// keeping English where PokeAPI has no translation
+func (s *pokemonService) localize(data *PokemonData, species *apiSpecies, lang string) {
+	if species != nil {
+		if name := pickName(species.Names, lang); name != "" {
+			data.Name = name
+		}
+	}
+
+	// Types holds capitalized PokeAPI names, so lowering them gives the slugs
+	slugs := strings.Split(strings.ToLower(data.Types), ", ")
+	typeNames := make([]string, len(slugs))
+	for i, slug := range slugs {
+		typeNames[i] = s.typeName(slug, lang)
+	}
+	data.Types = strings.Join(typeNames, ", ")
+
+	weaknesses := typechart.Latest().Defending(slugs...).Weaknesses
+	parts := make([]string, len(weaknesses))
+	for i, m := range weaknesses {
+		parts[i] = fmt.Sprintf("%s (%sx)", s.typeName(m.Type, lang), strconv.FormatFloat(m.Multiplier, 'f', -1, 64))
+	}
+	data.Weaknesses = strings.Join(parts, ", ")
+
+	abilityNames := make([]string, len(data.AbilityDetails))
+	for i, a := range data.AbilityDetails {
+		abilityNames[i] = a.Name
+	}
+	data.Abilities = strings.Join(abilityNames, ", ")
 }
 
-func (s *pokemonService) GetSearchStats() (*repository.SearchStats, error) {
+func (s *pokemonService) GetSearchStats(window repository.TimeWindow) (*repository.SearchStats, error) {
 	if s.searchRepo == nil {
 		return nil, fmt.Errorf("search repository not configured")
 	}
-	return s.searchRepo.GetStats()
+	stats, err := s.searchRepo.GetStats(window)
+	if err != nil || s.favorites == nil {
+		return stats, err
+	}
+
+	// Favorite counts are extras; stats are still served without them, e.g.
+	// before the favorites table is migrated
+	counts, err := s.favorites.CountByPokemon(window)
+	if err != nil {
+		log.Printf("search stats without favorite counts: %v", err)
+		return stats, nil
+	}
+	stats.AddFavorites(counts)
+	return stats, nil
+}
+
+// ListSearches returns the page of logged searches following an opaque cursor
+// (empty for the first page) and the cursor of the next page
+func (s *pokemonService) ListSearches(cursor string, limit int) ([]repository.PokemonSearch, string, error) {
+	if s.searchRepo == nil {
+		return nil, "", fmt.Errorf("search repository not configured")
+	}
+
+	var after *repository.Cursor
+	if cursor != "" {
+		decoded, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+	}
+
+	searches, next, err := s.searchRepo.ListSearches(after, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if next == nil {
+		return searches, "", nil
+	}
+	return searches, repository.EncodeCursor(*next), nil
 }
 
-func (s *pokemonService) transformData(raw map[string]interface{}) *PokemonData {
+// errNotPokemon is returned by transformData for documents that aren't a
+// pokemon resource, such as the list PokeAPI serves for an empty name. It
+// counts as a missing resource.
+var errNotPokemon = fmt.Errorf("%w: not a pokemon resource", errResourceNotFound)
+
+// transformData reads a pokemon resource. Every assertion is checked, so a
+// document of another shape is errNotPokemon rather than a panic.
+func (s *pokemonService) transformData(raw map[string]interface{}) (*PokemonData, error) {
+	id, ok := raw["id"].(float64)
+	if !ok || id < 1 {
+		return nil, errNotPokemon
+	}
+	name, ok := raw["name"].(string)
+	if !ok || name == "" {
+		return nil, errNotPokemon
+	}
 	data := &PokemonData{
-		ID:   int(raw["id"].(float64)),
-		Name: capitalize(raw["name"].(string)),
+		ID:   int(id),
+		Name: capitalize(name),
 	}
 
 	// Extract types
-	types := raw["types"].([]interface{})
-	typeNames := make([]string, len(types))
-	for i, t := range types {
-		typeMap := t.(map[string]interface{})
-		typeName := typeMap["type"].(map[string]interface{})["name"].(string)
-		typeNames[i] = capitalize(typeName)
+	types, _ := raw["types"].([]interface{})
+	if len(types) == 0 {
+		return nil, errNotPokemon
+	}
+	typeNames := make([]string, 0, len(types))
+	typeSlugs := make([]string, 0, len(types))
+	for _, t := range types {
+		typeName := nestedName(t, "type")
+		if typeName == "" {
+			return nil, errNotPokemon
+		}
+		typeNames = append(typeNames, capitalize(typeName))
+		typeSlugs = append(typeSlugs, typeName)
 	}
 	data.Types = strings.Join(typeNames, ", ")
+	data.Weaknesses = weaknessSummary(typeSlugs)
 
 	// Extract abilities
-	abilities := raw["abilities"].([]interface{})
-	abilityNames := make([]string, len(abilities))
-	for i, a := range abilities {
-		abilityMap := a.(map[string]interface{})
-		abilityName := abilityMap["ability"].(map[string]interface{})["name"].(string)
-		abilityNames[i] = capitalize(strings.ReplaceAll(abilityName, "-", " "))
+	abilities, _ := raw["abilities"].([]interface{})
+	abilityNames := make([]string, 0, len(abilities))
+	data.AbilityDetails = make([]PokemonAbility, 0, len(abilities))
+	for _, a := range abilities {
+		abilityName := nestedName(a, "ability")
+		if abilityName == "" {
+			continue
+		}
+		abilityMap, _ := a.(map[string]interface{})
+		abilityNames = append(abilityNames, capitalize(strings.ReplaceAll(abilityName, "-", " ")))
+		isHidden, _ := abilityMap["is_hidden"].(bool)
+		slot, _ := abilityMap["slot"].(float64)
+		data.AbilityDetails = append(data.AbilityDetails, PokemonAbility{
+			Name:     titleCase(abilityName),
+			Ability:  abilityName,
+			IsHidden: isHidden,
+			Slot:     int(slot),
+		})
 	}
 	data.Abilities = strings.Join(abilityNames, ", ")
 
-	// Extract stats
-	stats := raw["stats"].([]interface{})
+	// Extract stats, in PokeAPI's order: hp, attack, defense, special
+	// attack, special defense, speed
+	stats, _ := raw["stats"].([]interface{})
+	if len(stats) < 6 {
+		return nil, errNotPokemon
+	}
+	base := make([]int, 6)
+	for i := range base {
+		stat, _ := stats[i].(map[string]interface{})
+		value, ok := stat["base_stat"].(float64)
+		if !ok {
+			return nil, errNotPokemon
+		}
+		base[i] = int(value)
+	}
 	data.Stats = PokemonStats{
-		HP:        int(stats[0].(map[string]interface{})["base_stat"].(float64)),
-		Attack:    int(stats[1].(map[string]interface{})["base_stat"].(float64)),
-		Defense:   int(stats[2].(map[string]interface{})["base_stat"].(float64)),
-		SpAttack:  int(stats[3].(map[string]interface{})["base_stat"].(float64)),
-		SpDefense: int(stats[4].(map[string]interface{})["base_stat"].(float64)),
-		Speed:     int(stats[5].(map[string]interface{})["base_stat"].(float64)),
+		HP:        base[0],
+		Attack:    base[1],
+		Defense:   base[2],
+		SpAttack:  base[3],
+		SpDefense: base[4],
+		Speed:     base[5],
 	}
 
 	// Height and weight
-	height := raw["height"].(float64)
-	weight := raw["weight"].(float64)
+	height, _ := raw["height"].(float64)
+	weight, _ := raw["weight"].(float64)
 	data.Height = fmt.Sprintf("%.1f", height/10)
 	data.Weight = fmt.Sprintf("%.1f", weight/10)
 
 	// Sprite
-	sprites := raw["sprites"].(map[string]interface{})
+	sprites, _ := raw["sprites"].(map[string]interface{})
 	if other, ok := sprites["other"].(map[string]interface{}); ok {
 		if artwork, ok := other["official-artwork"].(map[string]interface{}); ok {
 			if front, ok := artwork["front_default"].(string); ok {
@@ -173,7 +454,16 @@ func (s *pokemonService) transformData(raw map[string]interface{}) *PokemonData
 		}
 	}
 
-	return data
+	return data, nil
+}
+
+// nestedName reads entry[key].name, as in {"type": {"name": "electric"}},
+// or "" when entry isn't shaped that way
+func nestedName(entry interface{}, key string) string {
+	m, _ := entry.(map[string]interface{})
+	ref, _ := m[key].(map[string]interface{})
+	name, _ := ref["name"].(string)
+	return name
 }
 
 func capitalize(s string) string {
@@ -0,0 +1,200 @@
+package services_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokeapifake"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+)
+
+// startPokeAPI runs the fake PokeAPI for the duration of the test and
+// returns a service pointed at it
+func startPokeAPI(t *testing.T, opts pokeapifake.Options, extra ...services.PokemonOption) (services.PokemonService, *pokeapifake.Server) {
+	t.Helper()
+	ts, fake, err := pokeapifake.Start(opts)
+	if err != nil {
+		t.Fatalf("start fake PokeAPI: %v", err)
+	}
+	t.Cleanup(ts.Close)
+
+	options := append([]services.PokemonOption{services.WithBaseURL(ts.URL + "/api/v2")}, extra...)
+	return services.NewPokemonService(nil, options...), fake
+}
+
+func TestGetPokemonFound(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{})
+
+	for _, name := range []string{"pikachu", "Pikachu", "25"} {
+		result, err := service.GetPokemon(name, services.MessageOptions{})
+		if err != nil {
+			t.Fatalf("GetPokemon(%q): %v", name, err)
+		}
+		if !result.Found {
+			t.Fatalf("GetPokemon(%q) not found: %s", name, result.Message)
+		}
+		if result.Data.ID != 25 || result.Data.Name != "Pikachu" || result.Data.Types != "Electric" {
+			t.Errorf("GetPokemon(%q) data = %d %s %s, want 25 Pikachu Electric", name, result.Data.ID, result.Data.Name, result.Data.Types)
+		}
+		if result.Data.Stats.Total() != 320 {
+			t.Errorf("GetPokemon(%q) stat total = %d, want 320", name, result.Data.Stats.Total())
+		}
+		if !strings.Contains(result.Message, "Pikachu") {
+			t.Errorf("GetPokemon(%q) message = %q, want it to name Pikachu", name, result.Message)
+		}
+	}
+}
+
+func TestGetPokemonNotFound(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{})
+
+	result, err := service.GetPokemon("missingno", services.MessageOptions{})
+	if err != nil {
+		t.Fatalf("GetPokemon: %v", err)
+	}
+	if result.Found {
+		t.Fatal("GetPokemon(missingno) found")
+	}
+	if !strings.Contains(result.Message, "missingno") {
+		t.Errorf("message = %q, want it to name the query", result.Message)
+	}
+	if result.Data == nil || result.Data.Name != "Not Found" {
+		t.Errorf("data = %+v, want the Not Found placeholder", result.Data)
+	}
+
+	if _, err := service.GetPokemonData("missingno", ""); !errors.Is(err, services.ErrPokemonNotFound) {
+		t.Errorf("GetPokemonData error = %v, want ErrPokemonNotFound", err)
+	}
+}
+
+func TestGetPokemonEscapesName(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{})
+
+	// Unescaped, these would fetch the pokemon list or a type
+	for _, name := range []string{"?limit=1", "../type/fire", "pikachu/.."} {
+		if _, err := service.GetPokemonData(name, ""); !errors.Is(err, services.ErrPokemonNotFound) {
+			t.Errorf("GetPokemonData(%q) error = %v, want ErrPokemonNotFound", name, err)
+		}
+	}
+	result, err := service.ComparePokemon([]string{"pikachu", "?limit=1"})
+	if err != nil || result.Found || len(result.NotFound) != 1 || result.NotFound[0] != "?limit=1" {
+		t.Errorf("ComparePokemon = %+v, %v; want ?limit=1 not found", result, err)
+	}
+}
+
+func TestComparePokemonLocalizedName(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{})
+
+	// Looking Pikachu up in Japanese teaches the service its Japanese name
+	if _, err := service.GetPokemonData("pikachu", "ja"); err != nil {
+		t.Fatalf("GetPokemonData: %v", err)
+	}
+	result, err := service.ComparePokemon([]string{"ピカチュウ", "mew"})
+	if err != nil {
+		t.Fatalf("ComparePokemon: %v", err)
+	}
+	if !result.Found || len(result.NotFound) != 0 || len(result.Data.Pokemon) != 2 {
+		t.Errorf("ComparePokemon = %+v, want Pikachu and Mew compared", result)
+	}
+}
+
+func TestAnalyzeTeamOddMembers(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{})
+
+	// Stored members that aren't Pokemon fail the analysis, not the server
+	for _, name := range []string{"", "?limit=1", "../type/fire"} {
+		members := []repository.TeamMember{{Pokemon: "pikachu"}, {Pokemon: name}}
+		if analysis, err := service.AnalyzeTeam(members, 0); err == nil {
+			t.Errorf("AnalyzeTeam with %q = %+v, want an error", name, analysis)
+		}
+	}
+	if _, err := service.CheckTeamMember(repository.TeamMember{Pokemon: "?limit=1"}); !errors.Is(err, services.ErrPokemonNotFound) {
+		t.Errorf("CheckTeamMember(?limit=1) = %v, want ErrPokemonNotFound", err)
+	}
+}
+
+func TestLoadNameAliases(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{})
+
+	// Nothing fetched Eevee's species yet
+	if _, err := service.GetPokemonData("Évoli", ""); !errors.Is(err, services.ErrPokemonNotFound) {
+		t.Fatalf("GetPokemonData(Évoli) before loading = %v, want ErrPokemonNotFound", err)
+	}
+	n, err := service.LoadNameAliases()
+	if err != nil || n != 8 {
+		t.Fatalf("LoadNameAliases = %d, %v; want the 8 fixture species", n, err)
+	}
+	for _, name := range []string{"Évoli", "イーブイ", "evoli"} {
+		data, err := service.GetPokemonData(name, "")
+		if err != nil || data.Name != "Eevee" {
+			t.Errorf("GetPokemonData(%q) = %+v, %v; want Eevee", name, data, err)
+		}
+	}
+}
+
+func TestGetPokemonUpstreamError(t *testing.T) {
+	service, fake := startPokeAPI(t, pokeapifake.Options{})
+
+	fake.FailPath("pokemon/pikachu", http.StatusServiceUnavailable)
+	if _, err := service.GetPokemon("pikachu", services.MessageOptions{}); err == nil {
+		t.Fatal("GetPokemon succeeded while PokeAPI answered 503")
+	}
+
+	// A 5xx isn't a missing Pokemon, and clearing it recovers
+	fake.FailPath("pokemon/pikachu", 0)
+	result, err := service.GetPokemon("pikachu", services.MessageOptions{})
+	if err != nil || !result.Found {
+		t.Fatalf("GetPokemon after recovery = %v, %v", result, err)
+	}
+}
+
+func TestGetPokemonErrorRate(t *testing.T) {
+	service, _ := startPokeAPI(t, pokeapifake.Options{ErrorRate: 1})
+
+	if _, err := service.GetPokemon("pikachu", services.MessageOptions{}); err == nil {
+		t.Fatal("GetPokemon succeeded with every request failing")
+	}
+}
+
+func TestGetPokemonLatency(t *testing.T) {
+	const latency = 100 * time.Millisecond
+
+	service, _ := startPokeAPI(t, pokeapifake.Options{Latency: latency})
+	start := time.Now()
+	if _, err := service.GetPokemon("pikachu", services.MessageOptions{}); err != nil {
+		t.Fatalf("GetPokemon: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("GetPokemon took %v, want at least the injected %v", elapsed, latency)
+	}
+
+	// A client timeout shorter than the latency fails the lookup
+	client := &http.Client{Timeout: latency / 4}
+	service, _ = startPokeAPI(t, pokeapifake.Options{Latency: latency}, services.WithHTTPClient(client))
+	if _, err := service.GetPokemon("pikachu", services.MessageOptions{}); err == nil {
+		t.Fatal("GetPokemon succeeded past the client timeout")
+	}
+}
+
+func TestGetPokemonCached(t *testing.T) {
+	service, fake := startPokeAPI(t, pokeapifake.Options{}, services.WithCache(services.NewMemoryCache(time.Minute, 100)))
+
+	if _, err := service.GetPokemon("pikachu", services.MessageOptions{}); err != nil {
+		t.Fatalf("GetPokemon: %v", err)
+	}
+	served := fake.Requests()
+
+	// The second lookup is answered from the cache, even with PokeAPI down
+	fake.SetErrorRate(1)
+	result, err := service.GetPokemon("pikachu", services.MessageOptions{})
+	if err != nil || !result.Found {
+		t.Fatalf("cached GetPokemon = %v, %v", result, err)
+	}
+	if fake.Requests() != served {
+		t.Errorf("cached lookup made %d PokeAPI requests", fake.Requests()-served)
+	}
+}
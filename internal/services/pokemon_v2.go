@@ -0,0 +1,136 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+)
+
+// PokemonV2Response is the /api/v2 lookup. Unlike PokemonData, every field
+// is structured: types and abilities are objects and sizes are numbers
+// with units.
+type PokemonV2Response struct {
+	Found   bool           `json:"found"`
+	Message string         `json:"message"`
+	Data    *PokemonV2Data `json:"data,omitempty"`
+	// Suggestions are close names when nothing was found
+	Suggestions []PokemonSuggestion `json:"suggestions,omitempty"`
+}
+
+type PokemonV2Data struct {
+	ID         int                 `json:"id"`
+	Name       string              `json:"name"`
+	Types      []PokemonType       `json:"types"`
+	Abilities  []PokemonAbility    `json:"abilities"`
+	Stats      PokemonStats        `json:"stats"`
+	StatTotal  int                 `json:"statTotal"`
+	Height     Dimension           `json:"height"`
+	Weight     Dimension           `json:"weight"`
+	Sprite     string              `json:"sprite"`
+	Weaknesses []typechart.Matchup `json:"weaknesses"`
+
+	// From the species; left empty when the species can't be fetched
+	Genus       string `json:"genus,omitempty"`
+	FlavorText  string `json:"flavorText,omitempty"`
+	Generation  int    `json:"generation,omitempty"`
+	IsLegendary bool   `json:"isLegendary,omitempty"`
+	IsMythical  bool   `json:"isMythical,omitempty"`
+	CaptureRate int    `json:"captureRate,omitempty"`
+}
+
+// PokemonType is one of a Pokemon's types, Type being the PokeAPI name
+type PokemonType struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Slot int    `json:"slot"`
+}
+
+// Dimension is a height or weight in metric and imperial units
+type Dimension struct {
+	Metric   Measurement `json:"metric"`
+	Imperial Measurement `json:"imperial"`
+}
+
+type Measurement struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// apiPokemonSize is the size of a pokemon resource, in decimetres and
+// hectograms
+type apiPokemonSize struct {
+	Height int `json:"height"`
+	Weight int `json:"weight"`
+}
+
+// GetPokemonV2 looks a Pokemon up like GetPokemon, returning the structured
+// v2 shape
+func (s *pokemonService) GetPokemonV2(nameOrID string, opts MessageOptions) (*PokemonV2Response, error) {
+	lang := opts.Lang
+	v1, body, err := s.lookupPokemon(nameOrID, lang, true)
+	if errors.Is(err, errResourceNotFound) {
+		message, err := s.notFoundMessage(nameOrID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &PokemonV2Response{
+			Found:       false,
+			Message:     message,
+			Suggestions: s.suggest(nameOrID),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.pokemonMessage(v1, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var typing apiPokemonTypes
+	if err := json.Unmarshal(body, &typing); err != nil {
+		return nil, err
+	}
+	var size apiPokemonSize
+	if err := json.Unmarshal(body, &size); err != nil {
+		return nil, err
+	}
+
+	data := &PokemonV2Data{
+		ID:          v1.ID,
+		Name:        v1.Name,
+		Types:       make([]PokemonType, len(typing.Types)),
+		Abilities:   v1.AbilityDetails,
+		Stats:       v1.Stats,
+		StatTotal:   v1.Stats.Total(),
+		Height:      newDimension(float64(size.Height)/10, "m", 3.28084, "ft"),
+		Weight:      newDimension(float64(size.Weight)/10, "kg", 2.20462, "lb"),
+		Sprite:      v1.Sprite,
+		Weaknesses:  typechart.Latest().Defending(typing.typesIn(0)...).Weaknesses,
+		Genus:       v1.Genus,
+		FlavorText:  v1.FlavorText,
+		Generation:  v1.Generation,
+		IsLegendary: v1.IsLegendary,
+		IsMythical:  v1.IsMythical,
+		CaptureRate: v1.CaptureRate,
+	}
+	for i, ref := range typing.Types {
+		data.Types[i] = PokemonType{Name: s.typeName(ref.Type.Name, lang), Type: ref.Type.Name, Slot: ref.Slot}
+	}
+
+	return &PokemonV2Response{
+		Found:   true,
+		Message: message,
+		Data:    data,
+	}, nil
+}
+
+// newDimension converts a metric value with a factor to the imperial unit
+func newDimension(metric float64, metricUnit string, factor float64, imperialUnit string) Dimension {
+	return Dimension{
+		Metric:   Measurement{Value: metric, Unit: metricUnit},
+		Imperial: Measurement{Value: math.Round(metric*factor*100) / 100, Unit: imperialUnit},
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// MaxPrefixResults caps one page of SearchByPrefix
+const MaxPrefixResults = 50
+
+// prefixWorkers bounds the concurrent lookups of a page
+const prefixWorkers = 8
+
+// PrefixSearch is a page of the Pokemon whose names start with a prefix.
+// Total counts every match, not only this page.
+type PrefixSearch struct {
+	Total   int               `json:"total"`
+	Results []PokemonResponse `json:"results"`
+}
+
+// SearchByPrefix looks up the Pokemon whose names start with prefix, in
+// name order, skipping offset matches and returning at most limit. Each
+// result has its message in the language and channel of opts. Browsing
+// isn't logged as searches.
+func (s *pokemonService) SearchByPrefix(prefix string, offset, limit int, opts MessageOptions) (*PrefixSearch, error) {
+	names, err := s.pokemonNames()
+	if err != nil {
+		return nil, err
+	}
+	prefix = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(prefix)), " ", "-")
+	if limit <= 0 || limit > MaxPrefixResults {
+		limit = MaxPrefixResults
+	}
+
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+	search := &PrefixSearch{Total: len(matches), Results: []PokemonResponse{}}
+	if offset >= len(matches) {
+		return search, nil
+	}
+	page := matches[offset:min(offset+limit, len(matches))]
+
+	type result struct {
+		response *PokemonResponse
+		err      error
+	}
+	results := make([]result, len(page))
+	sem := make(chan struct{}, prefixWorkers)
+	var wg sync.WaitGroup
+	for i, name := range page {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			data, _, err := s.lookupPokemon(name, opts.Lang, false)
+			if err != nil {
+				results[i] = result{err: err}
+				return
+			}
+			message, err := s.pokemonMessage(data, opts)
+			results[i] = result{&PokemonResponse{Found: true, Message: message, Data: data}, err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		switch {
+		// A name the index has but PokeAPI doesn't is left out
+		case errors.Is(r.err, errResourceNotFound):
+		case r.err != nil:
+			return nil, r.err
+		default:
+			search.Results = append(search.Results, *r.response)
+		}
+	}
+	return search, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/session"
+)
+
+// Session update errors
+var (
+	ErrUnknownFlow  = errors.New("unknown flow")
+	ErrInvalidEvent = errors.New("event not accepted in this state")
+	ErrMissingSlot  = errors.New("missing slot value")
+)
+
+type SessionService interface {
+	GetSession(telegramID string, opts MessageOptions) (*SessionResponse, error)
+	UpdateSession(telegramID string, update SessionUpdate, opts MessageOptions) (*SessionResponse, error)
+	EndSession(telegramID string) error
+}
+
+// SessionUpdate starts a flow, sets slots and/or sends an event, applied
+// in that order
+type SessionUpdate struct {
+	Flow  string            `json:"flow"`
+	Slots map[string]string `json:"slots"`
+	Event string            `json:"event"`
+}
+
+// SessionResponse is a session with what the frontend should do next:
+// show Prompt, then send one of Events (with the Expects slot for
+// "answer"). Result holds what the action of a state just entered
+// returned.
+type SessionResponse struct {
+	*session.Session
+	Prompt  string      `json:"prompt,omitempty"`
+	Expects string      `json:"expects,omitempty"`
+	Events  []string    `json:"events"`
+	Done    bool        `json:"done"`
+	Result  interface{} `json:"result,omitempty"`
+}
+
+type sessionService struct {
+	store   session.Store
+	users   UserService
+	pokemon PokemonService
+}
+
+func NewSessionService(store session.Store, users UserService, pokemon PokemonService) SessionService {
+	return &sessionService{store: store, users: users, pokemon: pokemon}
+}
+
+func (s *sessionService) GetSession(telegramID string, opts MessageOptions) (*SessionResponse, error) {
+	sess, err := s.store.Get(telegramID)
+	if err != nil {
+		return nil, err
+	}
+	return respond(sess, opts.Lang, nil, ""), nil
+}
+
+// UpdateSession applies an update to the session as read from the store.
+// Actions run without holding any lock, so when concurrent messages of a
+// user advance the same session, the store takes the first and the others
+// fail with session.ErrConflict.
+func (s *sessionService) UpdateSession(telegramID string, update SessionUpdate, opts MessageOptions) (*SessionResponse, error) {
+	current, err := s.store.Get(telegramID)
+	switch {
+	case errors.Is(err, session.ErrNotFound) && update.Flow != "":
+		// Starting a flow doesn't need a session
+	case err != nil:
+		return nil, err
+	}
+
+	sess := current
+	if update.Flow != "" {
+		flow, ok := session.Flows[update.Flow]
+		if !ok {
+			return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownFlow, update.Flow, strings.Join(session.FlowNames(), ", "))
+		}
+		sess = &session.Session{
+			TelegramID: telegramID,
+			Flow:       flow.Name,
+			State:      flow.Initial,
+			Slots:      make(map[string]string),
+			StartedAt:  time.Now(),
+		}
+		// Restarting replaces the session that was read
+		if current != nil {
+			sess.Version = current.Version
+		}
+	}
+	flow := session.Flows[sess.Flow]
+
+	for name, value := range update.Slots {
+		if value = strings.TrimSpace(value); value == "" {
+			delete(sess.Slots, name)
+		} else {
+			sess.Slots[name] = value
+		}
+	}
+
+	var result interface{}
+	var message string
+	if update.Event != "" {
+		state := flow.States[sess.State]
+		next, ok := flow.Next(sess.State, update.Event)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s accepts %s", ErrInvalidEvent, sess.State, strings.Join(flow.Events(sess.State), ", "))
+		}
+		if update.Event == session.EventAnswer && state.Slot != "" && sess.Slots[state.Slot] == "" {
+			return nil, fmt.Errorf("%w: %s needs slots.%s", ErrMissingSlot, sess.State, state.Slot)
+		}
+
+		result, message, err = s.run(flow.States[next].Action, sess, opts)
+		if err != nil {
+			return nil, err
+		}
+		sess.State = next
+	}
+
+	if err := s.store.Put(sess); err != nil {
+		return nil, err
+	}
+	return respond(sess, opts.Lang, result, message), nil
+}
+
+func (s *sessionService) EndSession(telegramID string) error {
+	return s.store.Delete(telegramID)
+}
+
+// run performs the action of a state being entered, returning its result
+// and, for results that are a reply, the message to show
+func (s *sessionService) run(action string, sess *session.Session, opts MessageOptions) (interface{}, string, error) {
+	switch action {
+	case session.ActionRegister:
+		result, err := s.users.Register(sess.TelegramID, sess.Slots["name"], sess.Slots["last_name"], sess.Slots["username"])
+		return result, "", err
+	case session.ActionLookup:
+		result, err := s.pokemon.GetPokemon(sess.Slots["pokemon"], opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return result, result.Message, nil
+	}
+	return nil, "", nil
+}
+
+// respond describes the current state of sess in lang
+func respond(sess *session.Session, lang string, result interface{}, message string) *SessionResponse {
+	flow := session.Flows[sess.Flow]
+	state := flow.States[sess.State]
+
+	prompt := message
+	if state.Prompt != "" {
+		args := make([]interface{}, len(state.PromptSlots))
+		for i, slot := range state.PromptSlots {
+			args[i] = sess.Slots[slot]
+		}
+		prompt = i18n.T(lang, state.Prompt, args...)
+	}
+
+	return &SessionResponse{
+		Session: sess,
+		Prompt:  prompt,
+		Expects: state.Slot,
+		Events:  flow.Events(sess.State),
+		Done:    state.Final,
+		Result:  result,
+	}
+}
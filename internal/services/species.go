@@ -0,0 +1,128 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// namedResource is PokeAPI's {name, url} reference to another resource
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// id returns the trailing id of the resource URL, e.g. "25" for
+// https://pokeapi.co/api/v2/pokemon-species/25/
+func (r namedResource) id() string {
+	url := strings.TrimSuffix(r.URL, "/")
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+// apiSpecies is the part of a pokemon-species resource the service reads
+type apiSpecies struct {
+	ID     int         `json:"id"`
+	Name   string      `json:"name"`
+	Names  []localName `json:"names"`
+	Genera []struct {
+		Genus    string        `json:"genus"`
+		Language namedResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   namedResource `json:"language"`
+	} `json:"flavor_text_entries"`
+	Generation     namedResource `json:"generation"`
+	IsLegendary    bool          `json:"is_legendary"`
+	IsMythical     bool          `json:"is_mythical"`
+	IsBaby         bool          `json:"is_baby"`
+	CaptureRate    int           `json:"capture_rate"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	EvolvesFromSpecies *namedResource `json:"evolves_from_species"`
+	Varieties          []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   namedResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// fetchSpecies loads a pokemon-species resource by name or id
+func (s *pokemonService) fetchSpecies(nameOrID string) (*apiSpecies, error) {
+	body, err := s.fetch("pokemon-species", nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	var species apiSpecies
+	if err := json.Unmarshal(body, &species); err != nil {
+		return nil, err
+	}
+	s.aliases.add(&species)
+	return &species, nil
+}
+
+// genus returns the English genus, e.g. "Mouse Pokémon"
+func (sp *apiSpecies) genus() string {
+	for _, g := range sp.Genera {
+		if g.Language.Name == "en" {
+			return g.Genus
+		}
+	}
+	return ""
+}
+
+// flavorText returns the most recent English Pokedex entry on one line.
+// PokeAPI lists entries oldest game first.
+func (sp *apiSpecies) flavorText() string {
+	text := ""
+	for _, entry := range sp.FlavorTextEntries {
+		if entry.Language.Name == "en" {
+			text = entry.FlavorText
+		}
+	}
+	// Entries keep the line and page breaks of the game text box
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// generation returns the generation number, e.g. 1 for "generation-i"
+func (sp *apiSpecies) generation() int {
+	return generationNumber(sp.Generation.Name)
+}
+
+// generationNumber parses a PokeAPI generation name such as "generation-iv"
+func generationNumber(name string) int {
+	_, roman, ok := strings.Cut(name, "-")
+	if !ok {
+		return 0
+	}
+	values := map[byte]int{'i': 1, 'v': 5, 'x': 10}
+	total, prev := 0, 0
+	for i := len(roman) - 1; i >= 0; i-- {
+		v := values[roman[i]]
+		if v < prev {
+			total -= v
+		} else {
+			total += v
+			prev = v
+		}
+	}
+	return total
+}
+
+// applySpecies copies the species details shown on the main lookup
+func (data *PokemonData) applySpecies(sp *apiSpecies) {
+	data.Genus = sp.genus()
+	data.FlavorText = sp.flavorText()
+	data.Generation = sp.generation()
+	data.IsLegendary = sp.IsLegendary
+	data.IsMythical = sp.IsMythical
+	data.CaptureRate = sp.CaptureRate
+}
+
+// titleCase turns a PokeAPI slug such as "thunder-stone" into "Thunder Stone"
+func titleCase(slug string) string {
+	words := strings.Split(slug, "-")
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, " ")
+}
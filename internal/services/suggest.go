@@ -0,0 +1,178 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokedex"
+)
+
+// maxSuggestions is how many close names a not-found lookup suggests
+const maxSuggestions = 3
+
+// PokemonSuggestion is a Pokemon whose name is close to a query that
+// matched nothing; Pokemon is the name to look it up by
+type PokemonSuggestion struct {
+	Name    string `json:"name"`
+	Pokemon string `json:"pokemon"`
+}
+
+// nameIndex holds every Pokemon name, loaded on first use from the
+// Pokedex snapshot or PokeAPI's list endpoint. A failed load is retried on
+// the next use.
+type nameIndex struct {
+	mu    sync.Mutex
+	names []string
+}
+
+// pokemonNames returns the name index, loading it if needed
+func (s *pokemonService) pokemonNames() ([]string, error) {
+	s.names.mu.Lock()
+	defer s.names.mu.Unlock()
+	if s.names.names != nil {
+		return s.names.names, nil
+	}
+
+	var names []string
+	if s.pokedex != nil {
+		names = s.pokedex.Names(pokedex.Pokemon)
+	}
+	if len(names) == 0 && !s.offline {
+		listed, err := s.listPokemon()
+		if err != nil {
+			return nil, err
+		}
+		names = listed
+	}
+	s.names.names = names
+	return names, nil
+}
+
+// listPokemon reads the names of all default Pokemon from PokeAPI
+func (s *pokemonService) listPokemon() ([]string, error) {
+	return s.listResource("pokemon")
+}
+
+// listResource reads every name of a PokeAPI resource from its list
+// endpoint, sorted. Forms (ids above 10000, such as "pikachu-rock-star")
+// are left out.
+func (s *pokemonService) listResource(resource string) ([]string, error) {
+	resp, err := s.client.Get(s.baseURL + "/" + resource + "?limit=100000")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PokeAPI returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Results []namedResource `json:"results"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(list.Results))
+	for _, r := range list.Results {
+		if id, err := strconv.Atoi(r.id()); err == nil && id > 10000 {
+			continue
+		}
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SuggestPokemon returns up to limit Pokemon names close to query: names
+// starting with it first, then by edit distance. Queries that are far from
+// every name get none.
+func (s *pokemonService) SuggestPokemon(query string, limit int) ([]PokemonSuggestion, error) {
+	names, err := s.pokemonNames()
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(query)), " ", "-")
+	if query == "" {
+		return nil, nil
+	}
+
+	// Allow about one typo per three letters
+	maxDistance := len([]rune(query)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	for _, name := range names {
+		switch d := editDistance(query, name); {
+		case len(query) >= 3 && strings.HasPrefix(name, query):
+			matches = append(matches, match{name, -1})
+		case d <= maxDistance:
+			matches = append(matches, match{name, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	suggestions := make([]PokemonSuggestion, len(matches))
+	for i, m := range matches {
+		suggestions[i] = PokemonSuggestion{Name: titleCase(m.name), Pokemon: m.name}
+	}
+	return suggestions, nil
+}
+
+// suggest is SuggestPokemon for not-found responses, where suggestions are
+// an extra: failing to load the names just leaves them out
+func (s *pokemonService) suggest(query string) []PokemonSuggestion {
+	suggestions, err := s.SuggestPokemon(query, maxSuggestions)
+	if err != nil {
+		return nil
+	}
+	return suggestions
+}
+
+// editDistance is the Levenshtein distance between a and b, counting a
+// swap of adjacent letters as one edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
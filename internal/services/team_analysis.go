@@ -0,0 +1,316 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+)
+
+// Bounds of a team, as in the games
+const (
+	MaxTeamSize  = 6
+	MaxTeamMoves = 4
+)
+
+// ErrInvalidTeam is returned for teams breaking the team rules
+var ErrInvalidTeam = errors.New("invalid team")
+
+// TeamAnalysis sizes up a team. Defense lists how the team takes each
+// attacking type; SharedWeaknesses are the types two or more members are
+// weak to, most shared first; Unresisted are the types no member resists
+// or is immune to.
+type TeamAnalysis struct {
+	Team             *repository.Team `json:"team,omitempty"`
+	Generation       int              `json:"generation,omitempty"`
+	Members          []AnalyzedMember `json:"members"`
+	SharedWeaknesses []TypeDefense    `json:"shared_weaknesses"`
+	Unresisted       []string         `json:"unresisted"`
+	Defense          []TypeDefense    `json:"defense"`
+	Offense          TeamOffense      `json:"offense"`
+	Stats            TeamStats        `json:"stats"`
+}
+
+type AnalyzedMember struct {
+	ID     int          `json:"id"`
+	Name   string       `json:"name"`
+	Types  []string     `json:"types"`
+	Sprite string       `json:"sprite"`
+	Stats  PokemonStats `json:"stats"`
+	Total  int          `json:"total"`
+	Moves  []TeamMove   `json:"moves"`
+}
+
+// TeamMove is a move chosen for a member. Power is null for moves without
+// fixed power.
+type TeamMove struct {
+	Move        string `json:"move"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DamageClass string `json:"damage_class"`
+	Power       *int   `json:"power"`
+}
+
+// TypeDefense is how the team takes hits of one attacking type: which
+// members are weak to it, resist it and are immune to it
+type TypeDefense struct {
+	Type   string   `json:"type"`
+	Weak   []string `json:"weak"`
+	Resist []string `json:"resist"`
+	Immune []string `json:"immune"`
+}
+
+// TeamOffense is what the chosen damaging moves hit best, per defending
+// type. Uncovered are the types none of them hits super effectively.
+// Status moves and members without moves add nothing.
+type TeamOffense struct {
+	Coverage       []TypeOffense `json:"coverage"`
+	SuperEffective []string      `json:"super_effective"`
+	Uncovered      []string      `json:"uncovered"`
+}
+
+// TypeOffense is the best multiplier the team's moves deal to a defending
+// type, with the moves dealing it. Multiplier is 0 without damaging moves.
+type TypeOffense struct {
+	Type       string    `json:"type"`
+	Multiplier float64   `json:"multiplier"`
+	Moves      []MoveRef `json:"moves"`
+}
+
+type MoveRef struct {
+	Pokemon string `json:"pokemon"`
+	Move    string `json:"move"`
+}
+
+// TeamStats adds up the members' base stats. Averages are rounded down.
+type TeamStats struct {
+	Totals       PokemonStats `json:"totals"`
+	Averages     PokemonStats `json:"averages"`
+	Total        int          `json:"total"`
+	AverageTotal int          `json:"average_total"`
+}
+
+// CheckTeamMember resolves a member's Pokemon to its PokeAPI name and its
+// moves to move names, checking the Pokemon can learn each of them in some
+// game. An unknown Pokemon is ErrPokemonNotFound.
+func (s *pokemonService) CheckTeamMember(member repository.TeamMember) (repository.TeamMember, error) {
+	nameOrID := strings.TrimSpace(member.Pokemon)
+	if nameOrID == "" {
+		return member, fmt.Errorf("%w: every member needs a Pokemon", ErrInvalidTeam)
+	}
+	body, err := s.fetchPokemon(nameOrID)
+	if errors.Is(err, errResourceNotFound) {
+		return member, ErrPokemonNotFound
+	}
+	if err != nil {
+		return member, err
+	}
+	var pokemon apiPokemonMoves
+	if err := json.Unmarshal(body, &pokemon); err != nil {
+		return member, err
+	}
+	name := capitalize(pokemon.Name)
+
+	if len(member.Moves) > MaxTeamMoves {
+		return member, fmt.Errorf("%w: %s has more than %d moves", ErrInvalidTeam, name, MaxTeamMoves)
+	}
+	learnable := make(map[string]bool, len(pokemon.Moves))
+	for _, m := range pokemon.Moves {
+		learnable[m.Move.Name] = true
+	}
+
+	checked := repository.TeamMember{Pokemon: pokemon.Name, Moves: []string{}}
+	seen := make(map[string]bool)
+	for _, move := range member.Moves {
+		slug := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(move)), " ", "-")
+		switch {
+		case !learnable[slug]:
+			return member, fmt.Errorf("%w: %s can't learn %s", ErrInvalidTeam, name, move)
+		case seen[slug]:
+			return member, fmt.Errorf("%w: %s has %s twice", ErrInvalidTeam, name, move)
+		}
+		seen[slug] = true
+		checked.Moves = append(checked.Moves, slug)
+	}
+	return checked, nil
+}
+
+// AnalyzeTeam computes the type coverage and stat totals of a team with
+// the chart of a generation, 0 meaning the current one. Members are
+// fetched concurrently; analyses aren't logged as searches.
+func (s *pokemonService) AnalyzeTeam(members []repository.TeamMember, generation int) (*TeamAnalysis, error) {
+	chart, err := typechart.ForGeneration(generation)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzed := make([]AnalyzedMember, len(members))
+	errs := make([]error, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member repository.TeamMember) {
+			defer wg.Done()
+			analyzed[i], errs[i] = s.analyzeMember(member, generation)
+		}(i, member)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	analysis := &TeamAnalysis{
+		Generation: generation,
+		Members:    analyzed,
+		Defense:    teamDefense(chart, analyzed),
+		Offense:    teamOffense(chart, analyzed),
+		Stats:      teamStats(analyzed),
+	}
+	analysis.SharedWeaknesses = []TypeDefense{}
+	analysis.Unresisted = []string{}
+	for _, d := range analysis.Defense {
+		if len(d.Weak) >= 2 {
+			analysis.SharedWeaknesses = append(analysis.SharedWeaknesses, d)
+		}
+		if len(members) > 0 && len(d.Resist) == 0 && len(d.Immune) == 0 {
+			analysis.Unresisted = append(analysis.Unresisted, d.Type)
+		}
+	}
+	sort.SliceStable(analysis.SharedWeaknesses, func(i, j int) bool {
+		return len(analysis.SharedWeaknesses[i].Weak) > len(analysis.SharedWeaknesses[j].Weak)
+	})
+	return analysis, nil
+}
+
+func (s *pokemonService) analyzeMember(member repository.TeamMember, generation int) (AnalyzedMember, error) {
+	if member.Pokemon == "" {
+		return AnalyzedMember{}, fmt.Errorf("%w: member without a Pokemon", ErrInvalidTeam)
+	}
+	body, err := s.fetch("pokemon", member.Pokemon)
+	if err != nil {
+		return AnalyzedMember{}, fmt.Errorf("failed to fetch %s: %w", member.Pokemon, err)
+	}
+	var rawData map[string]interface{}
+	if err := json.Unmarshal(body, &rawData); err != nil {
+		return AnalyzedMember{}, err
+	}
+	var typing apiPokemonTypes
+	if err := json.Unmarshal(body, &typing); err != nil {
+		return AnalyzedMember{}, err
+	}
+
+	data, err := s.transformData(rawData)
+	if err != nil {
+		return AnalyzedMember{}, fmt.Errorf("failed to read %s: %w", member.Pokemon, err)
+	}
+	analyzed := AnalyzedMember{
+		ID:     data.ID,
+		Name:   data.Name,
+		Types:  typing.typesIn(generation),
+		Sprite: data.Sprite,
+		Stats:  data.Stats,
+		Total:  data.Stats.Total(),
+		Moves:  make([]TeamMove, 0, len(member.Moves)),
+	}
+	for _, slug := range member.Moves {
+		body, err := s.fetch("move", slug)
+		if err != nil {
+			return AnalyzedMember{}, fmt.Errorf("failed to fetch move %s: %w", slug, err)
+		}
+		var move apiMove
+		if err := json.Unmarshal(body, &move); err != nil {
+			return AnalyzedMember{}, err
+		}
+		analyzed.Moves = append(analyzed.Moves, TeamMove{
+			Move:        move.Name,
+			Name:        move.displayName(),
+			Type:        move.Type.Name,
+			DamageClass: move.DamageClass.Name,
+			Power:       move.Power,
+		})
+	}
+	return analyzed, nil
+}
+
+// teamDefense groups the members by how they take each attacking type of
+// the chart
+func teamDefense(chart *typechart.Chart, members []AnalyzedMember) []TypeDefense {
+	defense := make([]TypeDefense, 0, len(chart.Types()))
+	for _, attack := range chart.Types() {
+		d := TypeDefense{Type: attack, Weak: []string{}, Resist: []string{}, Immune: []string{}}
+		for _, m := range members {
+			switch multiplier := chart.Against(attack, m.Types...); {
+			case multiplier == 0:
+				d.Immune = append(d.Immune, m.Name)
+			case multiplier > 1:
+				d.Weak = append(d.Weak, m.Name)
+			case multiplier < 1:
+				d.Resist = append(d.Resist, m.Name)
+			}
+		}
+		defense = append(defense, d)
+	}
+	return defense
+}
+
+// teamOffense finds, for each defending type, the damaging moves that hit
+// it hardest
+func teamOffense(chart *typechart.Chart, members []AnalyzedMember) TeamOffense {
+	offense := TeamOffense{Coverage: []TypeOffense{}, SuperEffective: []string{}, Uncovered: []string{}}
+	for _, defend := range chart.Types() {
+		o := TypeOffense{Type: defend, Moves: []MoveRef{}}
+		for _, m := range members {
+			for _, move := range m.Moves {
+				if move.DamageClass == "status" {
+					continue
+				}
+				ref := MoveRef{Pokemon: m.Name, Move: move.Name}
+				switch multiplier := chart.Effectiveness(move.Type, defend); {
+				case multiplier > o.Multiplier:
+					o.Multiplier, o.Moves = multiplier, []MoveRef{ref}
+				case multiplier == o.Multiplier && multiplier > 0:
+					o.Moves = append(o.Moves, ref)
+				}
+			}
+		}
+		offense.Coverage = append(offense.Coverage, o)
+		if o.Multiplier > 1 {
+			offense.SuperEffective = append(offense.SuperEffective, defend)
+		} else {
+			offense.Uncovered = append(offense.Uncovered, defend)
+		}
+	}
+	return offense
+}
+
+func teamStats(members []AnalyzedMember) TeamStats {
+	var stats TeamStats
+	for _, m := range members {
+		stats.Totals.HP += m.Stats.HP
+		stats.Totals.Attack += m.Stats.Attack
+		stats.Totals.Defense += m.Stats.Defense
+		stats.Totals.SpAttack += m.Stats.SpAttack
+		stats.Totals.SpDefense += m.Stats.SpDefense
+		stats.Totals.Speed += m.Stats.Speed
+	}
+	stats.Total = stats.Totals.Total()
+	if n := len(members); n > 0 {
+		stats.Averages = PokemonStats{
+			HP:        stats.Totals.HP / n,
+			Attack:    stats.Totals.Attack / n,
+			Defense:   stats.Totals.Defense / n,
+			SpAttack:  stats.Totals.SpAttack / n,
+			SpDefense: stats.Totals.SpDefense / n,
+			Speed:     stats.Totals.Speed / n,
+		}
+		stats.AverageTotal = stats.Total / n
+	}
+	return stats
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+)
+
+// maxTeamName is the longest team name, in characters
+const maxTeamName = 50
+
+type TeamService interface {
+	ListTeams(telegramID string) ([]repository.Team, error)
+	GetTeam(telegramID string, id int) (*repository.Team, error)
+	CreateTeam(telegramID string, input TeamInput) (*repository.Team, error)
+	UpdateTeam(telegramID string, id int, input TeamInput) (*repository.Team, error)
+	DeleteTeam(telegramID string, id int) error
+	AnalyzeTeam(telegramID string, id int, generation int) (*TeamAnalysis, error)
+}
+
+// TeamInput is a team as a user sends it: Pokemon by name, id or localized
+// name, with up to four moves each
+type TeamInput struct {
+	Name    string                  `json:"name"`
+	Members []repository.TeamMember `json:"members"`
+}
+
+type teamService struct {
+	repo    repository.TeamRepository
+	users   UserService
+	pokemon PokemonService
+}
+
+func NewTeamService(repo repository.TeamRepository, users UserService, pokemon PokemonService) TeamService {
+	return &teamService{repo: repo, users: users, pokemon: pokemon}
+}
+
+func (s *teamService) ListTeams(telegramID string) ([]repository.Team, error) {
+	if _, err := s.users.GetUserByTelegramID(telegramID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByUser(telegramID)
+}
+
+func (s *teamService) GetTeam(telegramID string, id int) (*repository.Team, error) {
+	if _, err := s.users.GetUserByTelegramID(telegramID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(telegramID, id)
+}
+
+func (s *teamService) CreateTeam(telegramID string, input TeamInput) (*repository.Team, error) {
+	if _, err := s.users.GetUserByTelegramID(telegramID); err != nil {
+		return nil, err
+	}
+	team, err := s.build(input)
+	if err != nil {
+		return nil, err
+	}
+
+	team.TelegramID = telegramID
+	if err := s.repo.Create(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// UpdateTeam replaces a team's name and members
+func (s *teamService) UpdateTeam(telegramID string, id int, input TeamInput) (*repository.Team, error) {
+	if _, err := s.GetTeam(telegramID, id); err != nil {
+		return nil, err
+	}
+	team, err := s.build(input)
+	if err != nil {
+		return nil, err
+	}
+
+	team.ID, team.TelegramID = id, telegramID
+	if err := s.repo.Update(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) DeleteTeam(telegramID string, id int) error {
+	if _, err := s.users.GetUserByTelegramID(telegramID); err != nil {
+		return err
+	}
+	return s.repo.Delete(telegramID, id)
+}
+
+// AnalyzeTeam reports the coverage and stats of a saved team
+func (s *teamService) AnalyzeTeam(telegramID string, id int, generation int) (*TeamAnalysis, error) {
+	team, err := s.GetTeam(telegramID, id)
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := s.pokemon.AnalyzeTeam(team.Members, generation)
+	if err != nil {
+		return nil, err
+	}
+	analysis.Team = team
+	return analysis, nil
+}
+
+// build checks a team against the team rules and resolves its Pokemon and
+// moves to PokeAPI names. Breaking a rule is ErrInvalidTeam.
+func (s *teamService) build(input TeamInput) (*repository.Team, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxTeamName {
+		return nil, fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidTeam, maxTeamName)
+	}
+	if len(input.Members) > MaxTeamSize {
+		return nil, fmt.Errorf("%w: at most %d Pokemon", ErrInvalidTeam, MaxTeamSize)
+	}
+
+	team := &repository.Team{Name: name, Members: make([]repository.TeamMember, 0, len(input.Members))}
+	seen := make(map[string]bool)
+	for _, member := range input.Members {
+		checked, err := s.pokemon.CheckTeamMember(member)
+		if errors.Is(err, ErrPokemonNotFound) {
+			return nil, fmt.Errorf("%w: unknown Pokemon %q", ErrInvalidTeam, member.Pokemon)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// One of each species, as in most competitive formats
+		if seen[checked.Pokemon] {
+			return nil, fmt.Errorf("%w: %s is on the team twice", ErrInvalidTeam, capitalize(checked.Pokemon))
+		}
+		seen[checked.Pokemon] = true
+		team.Members = append(team.Members, checked)
+	}
+	return team, nil
+}
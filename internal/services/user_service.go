@@ -6,15 +6,17 @@ import (
 
 	"github.com/yourusername/pokemon-chatbot-api/internal/models"
 	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
-	"gorm.io/gorm"
 )
 
 type UserService interface {
 	Register(telegramID, firstName, lastName, username string) (*RegisterResponse, error)
 	GetUserByTelegramID(telegramID string) (*models.User, error)
 	GetAllUsers() ([]models.User, error)
-	GetUsersPaginated(page, limit int) ([]models.User, int, error)
+	GetUsersPaginated(opts repository.UserListOptions, page, limit int) ([]models.User, int, error)
+	GetUsersAfter(opts repository.UserListOptions, cursor string, limit int) ([]models.User, string, error)
 	IsUserRegistered(telegramID string) (bool, error)
+	DeleteUser(telegramID string) error
+	SetLanguage(telegramID, language string) (*models.User, error)
 }
 
 type userService struct {
@@ -35,13 +37,11 @@ func NewUserService(repo repository.UserRepository) UserService {
 func (s *userService) Register(telegramID, firstName, lastName, username string) (*RegisterResponse, error) {
 	// Check if user exists
 	existingUser, err := s.repo.FindByTelegramID(telegramID)
-	if err == nil && existingUser != nil {
-		return &RegisterResponse{
-			Success: false,
-			Exists:  true,
-			Message: "User already registered",
-			User:    existingUser,
-		}, nil
+	if err == nil {
+		return alreadyRegistered(existingUser), nil
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
 	}
 
 	// Create new user
@@ -56,7 +56,15 @@ func (s *userService) Register(telegramID, firstName, lastName, username string)
 	}
 
 	if err := s.repo.Create(user); err != nil {
-		return nil, err
+		if !errors.Is(err, repository.ErrUserExists) {
+			return nil, err
+		}
+		// Lost a race with a concurrent registration of the same user
+		existingUser, err := s.repo.FindByTelegramID(telegramID)
+		if err != nil {
+			return nil, err
+		}
+		return alreadyRegistered(existingUser), nil
 	}
 
 	return &RegisterResponse{
@@ -74,25 +82,57 @@ func (s *userService) GetAllUsers() ([]models.User, error) {
 	return s.repo.FindAll()
 }
 
-func (s *userService) GetUsersPaginated(page, limit int) ([]models.User, int, error) {
-	users, err := s.repo.FindAllPaginated(page, limit)
-	if err != nil {
-		return nil, 0, err
+func (s *userService) GetUsersPaginated(opts repository.UserListOptions, page, limit int) ([]models.User, int, error) {
+	return s.repo.FindAllPaginated(opts, page, limit)
+}
+
+// GetUsersAfter returns the page of users following an opaque cursor (empty
+// for the first page) and the cursor of the next page, empty on the last one
+func (s *userService) GetUsersAfter(opts repository.UserListOptions, cursor string, limit int) ([]models.User, string, error) {
+	var after *repository.Cursor
+	if cursor != "" {
+		decoded, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
 	}
-	total, err := s.repo.Count()
+
+	users, next, err := s.repo.FindAllAfter(opts, after, limit)
 	if err != nil {
-		return nil, 0, err
+		return nil, "", err
 	}
-	return users, total, nil
+	if next == nil {
+		return users, "", nil
+	}
+	return users, repository.EncodeCursor(*next), nil
 }
 
 func (s *userService) IsUserRegistered(telegramID string) (bool, error) {
 	user, err := s.repo.FindByTelegramID(telegramID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, repository.ErrUserNotFound) {
 			return false, nil
 		}
 		return false, err
 	}
 	return user != nil, nil
 }
+
+func (s *userService) DeleteUser(telegramID string) error {
+	return s.repo.Delete(telegramID)
+}
+
+// SetLanguage stores the language the bot should answer a user in
+func (s *userService) SetLanguage(telegramID, language string) (*models.User, error) {
+	return s.repo.UpdateLanguage(telegramID, language)
+}
+
+func alreadyRegistered(user *models.User) *RegisterResponse {
+	return &RegisterResponse{
+		Success: false,
+		Exists:  true,
+		Message: "User already registered",
+		User:    user,
+	}
+}
@@ -0,0 +1,105 @@
+package session
+
+import "sort"
+
+// Events every flow understands
+const (
+	// EventAnswer fills the slot the current state asks for
+	EventAnswer = "answer"
+	EventYes    = "yes"
+	EventNo     = "no"
+	EventAgain  = "again"
+)
+
+// Actions the API runs when a flow enters a state
+const (
+	ActionRegister = "register"
+	ActionLookup   = "lookup"
+)
+
+// Flow is a small state machine: the session starts in Initial and moves
+// along the transitions of its current state
+type Flow struct {
+	Name    string           `json:"name"`
+	Initial string           `json:"initial"`
+	States  map[string]State `json:"states"`
+}
+
+// State is one step of a flow. Slot is the value an answer event must
+// carry; Prompt is the i18n key of what to ask, formatted with the values
+// of PromptSlots; Action runs on entering the state.
+type State struct {
+	Prompt      string            `json:"prompt,omitempty"`
+	PromptSlots []string          `json:"prompt_slots,omitempty"`
+	Slot        string            `json:"slot,omitempty"`
+	Action      string            `json:"action,omitempty"`
+	On          map[string]string `json:"on,omitempty"`
+	Final       bool              `json:"final,omitempty"`
+}
+
+// Flows are the flows sessions can run, by name
+var Flows = map[string]*Flow{
+	"registration": {
+		Name:    "registration",
+		Initial: "askName",
+		States: map[string]State{
+			"askName": {
+				Prompt: "session.ask_name",
+				Slot:   "name",
+				On:     map[string]string{EventAnswer: "confirmName"},
+			},
+			"confirmName": {
+				Prompt:      "session.confirm_name",
+				PromptSlots: []string{"name"},
+				On:          map[string]string{EventYes: "registerUser", EventNo: "askName"},
+			},
+			"registerUser": {
+				Prompt:      "session.registered",
+				PromptSlots: []string{"name"},
+				Action:      ActionRegister,
+				Final:       true,
+			},
+		},
+	},
+	"search": {
+		Name:    "search",
+		Initial: "askPokemon",
+		States: map[string]State{
+			"askPokemon": {
+				Prompt: "session.ask_pokemon",
+				Slot:   "pokemon",
+				On:     map[string]string{EventAnswer: "showPokemon"},
+			},
+			"showPokemon": {
+				Action: ActionLookup,
+				On:     map[string]string{EventAgain: "askPokemon"},
+			},
+		},
+	},
+}
+
+// FlowNames lists the flows, sorted
+func FlowNames() []string {
+	names := make([]string, 0, len(Flows))
+	for name := range Flows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Next returns the state an event leads to from state
+func (f *Flow) Next(state, event string) (string, bool) {
+	next, ok := f.States[state].On[event]
+	return next, ok
+}
+
+// Events lists the events state accepts, sorted
+func (f *Flow) Events(state string) []string {
+	events := make([]string, 0, len(f.States[state].On))
+	for event := range f.States[state].On {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
@@ -0,0 +1,119 @@
+// Package session keeps the state of multi-turn chat flows per Telegram
+// user, so a chat frontend only relays messages while the API tracks
+// where each conversation is. A session runs one Flow at a time, holds
+// the slot values collected so far and expires after a period without
+// updates.
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Store errors
+var (
+	// ErrNotFound is returned for users without a live session
+	ErrNotFound = errors.New("session not found")
+	// ErrConflict is returned by Put when the session changed since it
+	// was read
+	ErrConflict = errors.New("session changed by another update")
+)
+
+// Session is the conversation state of one user
+type Session struct {
+	TelegramID string            `json:"telegram_id"`
+	Flow       string            `json:"flow"`
+	State      string            `json:"state"`
+	Slots      map[string]string `json:"slots"`
+	StartedAt  time.Time         `json:"started_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	// Version counts the puts of the stored session; 0 is none stored
+	Version int `json:"version"`
+}
+
+// Store persists sessions. Put is a compare-and-swap: it stores s only
+// if the stored session is still at s.Version, treating a missing or
+// expired one as version 0, and ErrConflict otherwise. It stamps
+// UpdatedAt and ExpiresAt and bumps Version.
+type Store interface {
+	Get(telegramID string) (*Session, error)
+	Put(s *Session) error
+	Delete(telegramID string) error
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]Session
+}
+
+// NewMemoryStore keeps sessions in process for ttl after their last
+// update. Sessions are lost on restart and not shared between instances.
+func NewMemoryStore(ttl time.Duration) Store {
+	return &memoryStore{ttl: ttl, sessions: make(map[string]Session)}
+}
+
+func (m *memoryStore) Get(telegramID string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[telegramID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if time.Now().After(s.ExpiresAt) {
+		delete(m.sessions, telegramID)
+		return nil, ErrNotFound
+	}
+	return s.clone(), nil
+}
+
+func (m *memoryStore) Put(s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	current := 0
+	if stored, ok := m.sessions[s.TelegramID]; ok && !now.After(stored.ExpiresAt) {
+		current = stored.Version
+	}
+	if s.Version != current {
+		return ErrConflict
+	}
+
+	s.UpdatedAt = now
+	s.ExpiresAt = now.Add(m.ttl)
+	s.Version++
+	m.sessions[s.TelegramID] = *s.clone()
+
+	// Drop expired sessions as we go rather than with a sweeper goroutine
+	for id, other := range m.sessions {
+		if now.After(other.ExpiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) Delete(telegramID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[telegramID]; !ok {
+		return ErrNotFound
+	}
+	delete(m.sessions, telegramID)
+	return nil
+}
+
+// clone copies s so callers can't change stored slots
+func (s Session) clone() *Session {
+	slots := make(map[string]string, len(s.Slots))
+	for k, v := range s.Slots {
+		slots[k] = v
+	}
+	s.Slots = slots
+	return &s
+}
@@ -0,0 +1,118 @@
+package session_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/session"
+)
+
+func TestFlowTransitions(t *testing.T) {
+	tests := []struct {
+		flow, state, event string
+		next               string // "" when the event isn't accepted
+	}{
+		{"registration", "askName", session.EventAnswer, "confirmName"},
+		{"registration", "askName", session.EventYes, ""},
+		{"registration", "confirmName", session.EventYes, "registerUser"},
+		{"registration", "confirmName", session.EventNo, "askName"},
+		{"registration", "confirmName", session.EventAnswer, ""},
+		{"registration", "registerUser", session.EventAgain, ""},
+		{"search", "askPokemon", session.EventAnswer, "showPokemon"},
+		{"search", "showPokemon", session.EventAgain, "askPokemon"},
+		{"search", "showPokemon", session.EventNo, ""},
+	}
+	for _, tt := range tests {
+		next, ok := session.Flows[tt.flow].Next(tt.state, tt.event)
+		if next != tt.next || ok != (tt.next != "") {
+			t.Errorf("%s %s on %s = %q, %t; want %q", tt.flow, tt.state, tt.event, next, ok, tt.next)
+		}
+	}
+
+	if got := session.Flows["registration"].Events("confirmName"); !reflect.DeepEqual(got, []string{"no", "yes"}) {
+		t.Errorf("confirmName events = %v, want no and yes", got)
+	}
+}
+
+func TestFlowsAreClosed(t *testing.T) {
+	// Every transition leads to a state of the same flow, and states that
+	// take an answer say which slot it fills
+	for name, flow := range session.Flows {
+		if _, ok := flow.States[flow.Initial]; !ok {
+			t.Errorf("%s starts in unknown state %s", name, flow.Initial)
+		}
+		for stateName, state := range flow.States {
+			for event, next := range state.On {
+				if _, ok := flow.States[next]; !ok {
+					t.Errorf("%s %s on %s leads to unknown state %s", name, stateName, event, next)
+				}
+				if event == session.EventAnswer && state.Slot == "" {
+					t.Errorf("%s %s takes an answer without a slot", name, stateName)
+				}
+			}
+			if state.Final && len(state.On) != 0 {
+				t.Errorf("%s %s is final but has transitions", name, stateName)
+			}
+		}
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	store := session.NewMemoryStore(50 * time.Millisecond)
+
+	sess := &session.Session{TelegramID: "1", Flow: "search", State: "askPokemon", Slots: map[string]string{}}
+	if err := store.Put(sess); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !sess.ExpiresAt.After(sess.UpdatedAt) {
+		t.Errorf("Put stamped %v, expiring %v", sess.UpdatedAt, sess.ExpiresAt)
+	}
+	if _, err := store.Get("1"); err != nil {
+		t.Fatalf("Get before the TTL: %v", err)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if _, err := store.Get("1"); !errors.Is(err, session.ErrNotFound) {
+		t.Errorf("Get after the TTL = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete("1"); !errors.Is(err, session.ErrNotFound) {
+		t.Errorf("Delete after the TTL = %v, want ErrNotFound", err)
+	}
+
+	// An expired session counts as none, so a new one starts at version 0
+	if err := store.Put(&session.Session{TelegramID: "1", Flow: "search", Slots: map[string]string{}}); err != nil {
+		t.Errorf("Put over an expired session: %v", err)
+	}
+}
+
+func TestMemoryStoreConflict(t *testing.T) {
+	store := session.NewMemoryStore(time.Minute)
+	if err := store.Put(&session.Session{TelegramID: "1", Flow: "search", Slots: map[string]string{}}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Two updates read the same version; only the first may write
+	first, _ := store.Get("1")
+	second, _ := store.Get("1")
+	first.Slots["pokemon"] = "pikachu"
+	if err := store.Put(first); err != nil {
+		t.Fatalf("first Put: %v", err)
+	}
+	second.Slots["pokemon"] = "mew"
+	if err := store.Put(second); !errors.Is(err, session.ErrConflict) {
+		t.Errorf("second Put = %v, want ErrConflict", err)
+	}
+
+	stored, _ := store.Get("1")
+	if stored.Version != 2 || stored.Slots["pokemon"] != "pikachu" {
+		t.Errorf("stored = version %d with %v, want 2 with pikachu", stored.Version, stored.Slots)
+	}
+
+	// Gets are copies
+	stored.Slots["pokemon"] = "eevee"
+	if again, _ := store.Get("1"); again.Slots["pokemon"] != "pikachu" {
+		t.Errorf("changing a got session changed the store: %v", again.Slots)
+	}
+}
@@ -0,0 +1,330 @@
+package supabasefake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// condition is one column.operator.value filter
+type condition struct {
+	column *Column
+	op     string
+	negate bool
+	value  interface{}   // parsed operand; nil for is.null
+	values []interface{} // operands of in
+	like   *regexp.Regexp
+}
+
+// node is a filter tree: either a condition or an and/or group
+type node struct {
+	cond     *condition
+	or       bool
+	negate   bool
+	children []*node
+}
+
+func (n *node) match(row map[string]interface{}) bool {
+	var ok bool
+	if n.cond != nil {
+		ok = n.cond.match(row)
+	} else {
+		ok = !n.or
+		for _, child := range n.children {
+			if child.match(row) == n.or {
+				ok = n.or
+				break
+			}
+		}
+	}
+	return ok != n.negate
+}
+
+func (c *condition) match(row map[string]interface{}) bool {
+	v := row[c.column.Name]
+
+	if c.op == "is" {
+		var ok bool
+		if c.value == nil {
+			ok = v == nil
+		} else {
+			ok = v == c.value
+		}
+		return ok != c.negate
+	}
+
+	// Comparisons with NULL are never true, negated or not
+	if v == nil {
+		return false
+	}
+
+	var ok bool
+	switch c.op {
+	case "eq":
+		ok = compare(v, c.value) == 0
+	case "neq":
+		ok = compare(v, c.value) != 0
+	case "gt":
+		ok = compare(v, c.value) > 0
+	case "gte":
+		ok = compare(v, c.value) >= 0
+	case "lt":
+		ok = compare(v, c.value) < 0
+	case "lte":
+		ok = compare(v, c.value) <= 0
+	case "like", "ilike":
+		ok = c.like.MatchString(fmt.Sprint(encode(v)))
+	case "in":
+		for _, candidate := range c.values {
+			if compare(v, candidate) == 0 {
+				ok = true
+				break
+			}
+		}
+	}
+	return ok != c.negate
+}
+
+// parseFilter parses a column filter param value such as "eq.5",
+// "not.is.null" or "in.(1,2)". quoted tells whether the operand may be
+// double-quoted, as it can inside logic trees.
+func (t *Table) parseFilter(columnName, expr string, quoted bool) (*condition, error) {
+	column, ok := t.column(columnName)
+	if !ok {
+		return nil, unknownColumn(t.Name, columnName)
+	}
+
+	c := &condition{column: column}
+	if rest, ok := strings.CutPrefix(expr, "not."); ok {
+		c.negate = true
+		expr = rest
+	}
+
+	op, operand, ok := strings.Cut(expr, ".")
+	if !ok {
+		return nil, parseError(expr)
+	}
+	c.op = op
+
+	var err error
+	switch op {
+	case "eq", "neq", "gt", "gte", "lt", "lte":
+		if quoted {
+			operand = unquote(operand)
+		}
+		c.value, err = column.parse(operand)
+	case "like", "ilike":
+		if quoted {
+			operand = unquote(operand)
+		}
+		c.like, err = likePattern(operand, op == "ilike")
+	case "is":
+		switch strings.ToLower(operand) {
+		case "null":
+		case "true":
+			c.value = true
+		case "false":
+			c.value = false
+		default:
+			return nil, parseError(expr)
+		}
+	case "in":
+		if !strings.HasPrefix(operand, "(") || !strings.HasSuffix(operand, ")") {
+			return nil, parseError(expr)
+		}
+		for _, item := range splitTopLevel(operand[1 : len(operand)-1]) {
+			v, err := column.parse(unquote(item))
+			if err != nil {
+				return nil, err
+			}
+			c.values = append(c.values, v)
+		}
+	default:
+		return nil, parseError(expr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseLogic parses an or=/and= tree such as
+// "(a.eq.1,b.is.null,and(c.gt.2,d.lt.3))"
+func (t *Table) parseLogic(or bool, expr string) (*node, error) {
+	if !strings.HasPrefix(expr, "(") || !strings.HasSuffix(expr, ")") {
+		return nil, parseError(expr)
+	}
+
+	group := &node{or: or}
+	for _, item := range splitTopLevel(expr[1 : len(expr)-1]) {
+		negate := false
+		if rest, ok := strings.CutPrefix(item, "not."); ok && (strings.HasPrefix(rest, "and(") || strings.HasPrefix(rest, "or(")) {
+			negate = true
+			item = rest
+		}
+
+		switch {
+		case strings.HasPrefix(item, "and("):
+			child, err := t.parseLogic(false, item[len("and"):])
+			if err != nil {
+				return nil, err
+			}
+			child.negate = negate
+			group.children = append(group.children, child)
+		case strings.HasPrefix(item, "or("):
+			child, err := t.parseLogic(true, item[len("or"):])
+			if err != nil {
+				return nil, err
+			}
+			child.negate = negate
+			group.children = append(group.children, child)
+		default:
+			column, expr, ok := strings.Cut(item, ".")
+			if !ok {
+				return nil, parseError(item)
+			}
+			cond, err := t.parseFilter(column, expr, true)
+			if err != nil {
+				return nil, err
+			}
+			group.children = append(group.children, &node{cond: cond})
+		}
+	}
+	return group, nil
+}
+
+// splitTopLevel splits s on commas outside parentheses and double quotes
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inQuotes, escaped := false, false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// unquote strips PostgREST double quotes and backslash escapes
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	var b strings.Builder
+	escaped := false
+	for _, r := range s[1 : len(s)-1] {
+		if !escaped && r == '\\' {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// likePattern compiles a PostgREST LIKE pattern, where * (or %) matches any
+// run of characters, _ one character, and a backslash escapes the next one
+func likePattern(pattern string, insensitive bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if insensitive {
+		b.WriteString("(?is)")
+	} else {
+		b.WriteString("(?s)")
+	}
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*', r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// orderTerm is one column of an order param
+type orderTerm struct {
+	column     string
+	desc       bool
+	nullsFirst bool
+}
+
+// parseOrder parses "col.desc.nullslast,id.asc". Like PostgreSQL, nulls
+// sort last ascending and first descending unless told otherwise.
+func (t *Table) parseOrder(expr string) ([]orderTerm, error) {
+	var terms []orderTerm
+	for _, item := range strings.Split(expr, ",") {
+		parts := strings.Split(item, ".")
+		if _, ok := t.column(parts[0]); !ok {
+			return nil, unknownColumn(t.Name, parts[0])
+		}
+		term := orderTerm{column: parts[0]}
+		nullsSet := false
+		for _, modifier := range parts[1:] {
+			switch modifier {
+			case "asc":
+				term.desc = false
+			case "desc":
+				term.desc = true
+			case "nullsfirst":
+				term.nullsFirst, nullsSet = true, true
+			case "nullslast":
+				term.nullsFirst, nullsSet = false, true
+			default:
+				return nil, parseError(item)
+			}
+		}
+		if !nullsSet {
+			term.nullsFirst = term.desc
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// less reports whether row a sorts before row b
+func less(terms []orderTerm, a, b map[string]interface{}) bool {
+	for _, term := range terms {
+		va, vb := a[term.column], b[term.column]
+		switch {
+		case va == nil && vb == nil:
+			continue
+		case va == nil:
+			return term.nullsFirst
+		case vb == nil:
+			return !term.nullsFirst
+		}
+		if cmp := compare(va, vb); cmp != 0 {
+			return (cmp < 0) != term.desc
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package supabasefake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType is the SQL type of a column, which decides how filter values
+// and JSON payloads are parsed and compared
+type ColumnType int
+
+const (
+	Serial ColumnType = iota // auto-incrementing integer
+	Integer
+	Text
+	Boolean
+	Timestamp // timestamp without time zone, stored in UTC
+	JSONB     // any JSON value, stored compacted
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case Serial, Integer:
+		return "integer"
+	case Boolean:
+		return "boolean"
+	case Timestamp:
+		return "timestamp without time zone"
+	case JSONB:
+		return "jsonb"
+	default:
+		return "text"
+	}
+}
+
+// Column describes one table column
+type Column struct {
+	Name    string
+	Type    ColumnType
+	NotNull bool
+	// Default is the SQL default used when an insert omits the column, e.g.
+	// "true" or "now()". Empty means NULL.
+	Default string
+}
+
+// Table describes a table the way its migration creates it
+type Table struct {
+	Name       string
+	Columns    []Column
+	PrimaryKey string
+	// Unique lists unique constraints, each a set of columns
+	Unique [][]string
+}
+
+// Tables returns the schema created by internal/migrations. Keep it in sync
+// when adding a migration.
+func Tables() []Table {
+	return []Table{
+		{
+			Name: "users",
+			Columns: []Column{
+				{Name: "id", Type: Serial, NotNull: true},
+				{Name: "telegram_id", Type: Text, NotNull: true},
+				{Name: "username", Type: Text},
+				{Name: "first_name", Type: Text, NotNull: true},
+				{Name: "last_name", Type: Text},
+				{Name: "registered_at", Type: Timestamp, Default: "now()"},
+				{Name: "last_active", Type: Timestamp, Default: "now()"},
+				{Name: "language", Type: Text},
+			},
+			PrimaryKey: "id",
+			Unique:     [][]string{{"telegram_id"}},
+		},
+		{
+			Name: "pokemon_searches",
+			Columns: []Column{
+				{Name: "id", Type: Serial, NotNull: true},
+				{Name: "pokemon_name", Type: Text, NotNull: true},
+				{Name: "pokemon_id", Type: Integer},
+				{Name: "found", Type: Boolean, Default: "true"},
+				{Name: "searched_at", Type: Timestamp, Default: "now()"},
+			},
+			PrimaryKey: "id",
+		},
+		{
+			Name: "favorites",
+			Columns: []Column{
+				{Name: "id", Type: Serial, NotNull: true},
+				{Name: "telegram_id", Type: Text, NotNull: true},
+				{Name: "pokemon_name", Type: Text, NotNull: true},
+				{Name: "pokemon_id", Type: Integer},
+				{Name: "created_at", Type: Timestamp, Default: "now()"},
+			},
+			PrimaryKey: "id",
+			Unique:     [][]string{{"telegram_id", "pokemon_name"}},
+		},
+		{
+			Name: "teams",
+			Columns: []Column{
+				{Name: "id", Type: Serial, NotNull: true},
+				{Name: "telegram_id", Type: Text, NotNull: true},
+				{Name: "name", Type: Text, NotNull: true},
+				{Name: "members", Type: JSONB, NotNull: true, Default: "[]"},
+				{Name: "created_at", Type: Timestamp, Default: "now()"},
+				{Name: "updated_at", Type: Timestamp, Default: "now()"},
+			},
+			PrimaryKey: "id",
+			Unique:     [][]string{{"telegram_id", "name"}},
+		},
+	}
+}
+
+func (t *Table) column(name string) (*Column, bool) {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i], true
+		}
+	}
+	return nil, false
+}
+
+// constraints returns the primary key and unique constraints with their
+// PostgreSQL names
+func (t *Table) constraints() []constraint {
+	var out []constraint
+	if t.PrimaryKey != "" {
+		out = append(out, constraint{name: t.Name + "_pkey", columns: []string{t.PrimaryKey}})
+	}
+	for _, columns := range t.Unique {
+		out = append(out, constraint{
+			name:    t.Name + "_" + strings.Join(columns, "_") + "_key",
+			columns: columns,
+		})
+	}
+	return out
+}
+
+type constraint struct {
+	name    string
+	columns []string
+}
+
+// timestampLayout is how PostgREST renders timestamp without time zone
+const timestampLayout = "2006-01-02T15:04:05.999999"
+
+var timestampInputs = []string{
+	time.RFC3339Nano,
+	timestampLayout,
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04:05.999999-07",
+	"2006-01-02",
+}
+
+// parse converts a filter operand to the column's Go representation:
+// int64, string, bool, time.Time or json.RawMessage
+func (c *Column) parse(s string) (interface{}, error) {
+	switch c.Type {
+	case JSONB:
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, []byte(s)); err != nil {
+			return nil, invalidInput(c.Type, s)
+		}
+		return json.RawMessage(compacted.Bytes()), nil
+	case Serial, Integer:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, invalidInput(c.Type, s)
+		}
+		return n, nil
+	case Boolean:
+		switch strings.ToLower(s) {
+		case "true", "t", "yes", "on", "1":
+			return true, nil
+		case "false", "f", "no", "off", "0":
+			return false, nil
+		}
+		return nil, invalidInput(c.Type, s)
+	case Timestamp:
+		if s == "now" || s == "now()" {
+			return time.Now().UTC(), nil
+		}
+		for _, layout := range timestampInputs {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return nil, invalidInput(c.Type, s)
+	default:
+		return s, nil
+	}
+}
+
+// decode converts a JSON payload value to the column's Go representation
+func (c *Column) decode(v interface{}) (interface{}, error) {
+	if c.Type == JSONB && v != nil {
+		// A JSON string stays a string, as it does in PostgreSQL
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return c.parse(string(data))
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return c.parse(v)
+	case json.Number:
+		return c.parse(v.String())
+	case bool:
+		if c.Type == Text {
+			return strconv.FormatBool(v), nil
+		}
+		return c.parse(strconv.FormatBool(v))
+	default:
+		return nil, &apiError{
+			status:  400,
+			Code:    "22P02",
+			Message: fmt.Sprintf("invalid input for column %q of type %s", c.Name, c.Type),
+		}
+	}
+}
+
+// defaultValue evaluates the column default for a new row
+func (c *Column) defaultValue() (interface{}, error) {
+	if c.Default == "" {
+		return nil, nil
+	}
+	return c.parse(c.Default)
+}
+
+// encode renders a stored value the way PostgREST serializes it
+func encode(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(timestampLayout)
+	}
+	return v
+}
+
+// compare orders two non-null values of the same column
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		b := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case bool:
+		b := b.(bool)
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		}
+		return 1
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case json.RawMessage:
+		return bytes.Compare(a, b.(json.RawMessage))
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+func invalidInput(t ColumnType, s string) *apiError {
+	return &apiError{
+		status:  400,
+		Code:    "22P02",
+		Message: fmt.Sprintf("invalid input syntax for type %s: %q", t, s),
+	}
+}
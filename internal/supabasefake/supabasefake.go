@@ -0,0 +1,755 @@
+// Package supabasefake is an in-process stand-in for the Supabase REST API
+// (PostgREST) backed by in-memory tables, so code built on
+// repository.SupabaseClient can run without a Supabase project.
+//
+// It implements the subset the repositories use: select with column
+// filters, or/and trees, order, limit/offset and Range, Prefer
+// return=representation and count=exact, single-object responses, inserts,
+// upserts, PATCH and DELETE. Constraint violations come back as the same
+// PostgreSQL error codes Supabase returns. Text compares byte-wise rather
+// than by locale collation.
+package supabasefake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server serves tables under /rest/v1. It implements http.Handler; its URL
+// is what repository.NewSupabaseClient expects as the Supabase URL.
+type Server struct {
+	mu     sync.Mutex
+	apiKey string
+	tables map[string]*table
+}
+
+type table struct {
+	Table
+	rows []map[string]interface{}
+	seq  int64
+}
+
+// New creates a server with empty tables. With no tables it uses Tables().
+func New(tables ...Table) *Server {
+	if len(tables) == 0 {
+		tables = Tables()
+	}
+	s := &Server{tables: make(map[string]*table)}
+	for _, t := range tables {
+		s.tables[t.Name] = &table{Table: t}
+	}
+	return s
+}
+
+// Start runs a server on a local port and returns the httptest server,
+// whose URL is the Supabase URL to use
+func Start(tables ...Table) (*httptest.Server, *Server) {
+	s := New(tables...)
+	return httptest.NewServer(s), s
+}
+
+// RequireAPIKey rejects requests whose apikey header is not key, like the
+// Supabase gateway does. An empty key accepts every request.
+func (s *Server) RequireAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+}
+
+// Seed inserts rows into a table, applying defaults and constraints as an
+// insert through the API would
+func (s *Server) Seed(tableName string, rows ...map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tables[tableName]
+	if !ok {
+		return unknownTable(tableName)
+	}
+	for _, row := range rows {
+		// Round-trip through JSON so callers can pass Go values
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		payload, err := decodePayload(data)
+		if err != nil {
+			return err
+		}
+		if _, err := t.insert(payload, nil, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rows returns a copy of a table's rows as PostgREST would serialize them
+func (s *Server) Rows(tableName string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tables[tableName]
+	if !ok {
+		return nil
+	}
+	return t.render(t.rows, nil)
+}
+
+// Reset empties every table and restarts their sequences
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tables {
+		t.rows = nil
+		t.seq = 0
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.apiKey != "" && r.Header.Get("apikey") != s.apiKey {
+		writeError(w, &apiError{status: http.StatusUnauthorized, Message: "Invalid API key"})
+		return
+	}
+
+	name, ok := strings.CutPrefix(r.URL.Path, "/rest/v1/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		writeError(w, &apiError{status: http.StatusNotFound, Code: "PGRST125", Message: "Invalid path specified in request URL"})
+		return
+	}
+	t, ok := s.tables[name]
+	if !ok {
+		writeError(w, unknownTable(name))
+		return
+	}
+
+	req, err := t.parseRequest(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var resp *response
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		resp, err = t.read(req)
+	case http.MethodPost:
+		resp, err = t.create(req)
+	case http.MethodPatch:
+		resp, err = t.update(req)
+	case http.MethodDelete:
+		resp, err = t.remove(req)
+	default:
+		err = &apiError{status: http.StatusMethodNotAllowed, Message: "Unsupported HTTP method: " + r.Method}
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp.write(w, r.Method == http.MethodHead)
+}
+
+// request is a parsed PostgREST request
+type request struct {
+	filters        []*node
+	columns        []string // nil means all
+	order          []orderTerm
+	limit, offset  int
+	hasLimit       bool
+	count          bool
+	representation bool
+	single         bool
+	upsert         bool
+	ignoreDupes    bool
+	onConflict     []string
+	body           []byte
+}
+
+func (t *table) parseRequest(r *http.Request) (*request, error) {
+	req := &request{}
+
+	for key, values := range r.URL.Query() {
+		for _, value := range values {
+			switch key {
+			case "select":
+				if value != "*" {
+					for _, column := range strings.Split(value, ",") {
+						if _, ok := t.column(column); !ok {
+							return nil, unknownColumn(t.Name, column)
+						}
+						req.columns = append(req.columns, column)
+					}
+				}
+			case "order":
+				terms, err := t.parseOrder(value)
+				if err != nil {
+					return nil, err
+				}
+				req.order = terms
+			case "limit", "offset":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, parseError(key + "=" + value)
+				}
+				if key == "limit" {
+					req.limit, req.hasLimit = n, true
+				} else {
+					req.offset = n
+				}
+			case "on_conflict":
+				req.onConflict = strings.Split(value, ",")
+			case "or", "and":
+				tree, err := t.parseLogic(key == "or", value)
+				if err != nil {
+					return nil, err
+				}
+				req.filters = append(req.filters, tree)
+			default:
+				cond, err := t.parseFilter(key, value, false)
+				if err != nil {
+					return nil, err
+				}
+				req.filters = append(req.filters, &node{cond: cond})
+			}
+		}
+	}
+
+	if header := r.Header.Get("Range"); header != "" {
+		fromStr, toStr, _ := strings.Cut(header, "-")
+		from, err := strconv.Atoi(fromStr)
+		if err != nil {
+			return nil, &apiError{status: http.StatusRequestedRangeNotSatisfiable, Code: "PGRST103", Message: "Requested range not satisfiable"}
+		}
+		req.offset = from
+		if to, err := strconv.Atoi(toStr); err == nil {
+			req.limit, req.hasLimit = to-from+1, true
+		}
+	}
+
+	for _, pref := range strings.Split(r.Header.Get("Prefer"), ",") {
+		switch strings.TrimSpace(pref) {
+		case "return=representation":
+			req.representation = true
+		case "count=exact", "count=planned", "count=estimated":
+			req.count = true
+		case "resolution=merge-duplicates":
+			req.upsert = true
+		case "resolution=ignore-duplicates":
+			req.upsert, req.ignoreDupes = true, true
+		}
+	}
+	req.single = strings.Contains(r.Header.Get("Accept"), "application/vnd.pgrst.object+json")
+
+	if r.Body != nil {
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(r.Body); err != nil {
+			return nil, err
+		}
+		req.body = buf.Bytes()
+	}
+	return req, nil
+}
+
+// response is what a handler produced, written once the request succeeds
+type response struct {
+	status       int
+	rows         []map[string]interface{}
+	hasBody      bool
+	single       bool
+	contentRange string
+}
+
+func (r *response) write(w http.ResponseWriter, head bool) {
+	if r.contentRange != "" {
+		w.Header().Set("Content-Range", r.contentRange)
+	}
+	if !r.hasBody {
+		w.WriteHeader(r.status)
+		return
+	}
+
+	var body []byte
+	if r.single {
+		if len(r.rows) != 1 {
+			writeError(w, &apiError{
+				status:  http.StatusNotAcceptable,
+				Code:    "PGRST116",
+				Message: "JSON object requested, multiple (or no) rows returned",
+				Details: fmt.Sprintf("The result contains %d rows", len(r.rows)),
+			})
+			return
+		}
+		body, _ = json.Marshal(r.rows[0])
+		w.Header().Set("Content-Type", "application/vnd.pgrst.object+json; charset=utf-8")
+	} else {
+		if r.rows == nil {
+			r.rows = []map[string]interface{}{}
+		}
+		body, _ = json.Marshal(r.rows)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	w.WriteHeader(r.status)
+	if !head {
+		w.Write(body)
+	}
+}
+
+func (t *table) matching(req *request) []map[string]interface{} {
+	var rows []map[string]interface{}
+	for _, row := range t.rows {
+		ok := true
+		for _, filter := range req.filters {
+			if !filter.match(row) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (t *table) read(req *request) (*response, error) {
+	rows := t.matching(req)
+	total := len(rows)
+
+	if len(req.order) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return less(req.order, rows[i], rows[j])
+		})
+	}
+
+	start := min(req.offset, len(rows))
+	end := len(rows)
+	if req.hasLimit {
+		end = min(start+req.limit, len(rows))
+	}
+	rows = rows[start:end]
+
+	totalStr := "*"
+	if req.count {
+		totalStr = strconv.Itoa(total)
+	}
+	contentRange := "*/" + totalStr
+	if len(rows) > 0 {
+		contentRange = fmt.Sprintf("%d-%d/%s", start, start+len(rows)-1, totalStr)
+	}
+
+	status := http.StatusOK
+	if req.count && len(rows) < total && !req.single {
+		status = http.StatusPartialContent
+	}
+
+	return &response{
+		status:       status,
+		rows:         t.render(rows, req.columns),
+		hasBody:      true,
+		single:       req.single,
+		contentRange: contentRange,
+	}, nil
+}
+
+func (t *table) create(req *request) (*response, error) {
+	payloads, err := decodePayloads(req.body)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflict []string
+	if req.upsert {
+		conflict = req.onConflict
+		if len(conflict) == 0 {
+			conflict = []string{t.PrimaryKey}
+		}
+		if t.constraintOn(conflict) == nil {
+			return nil, &apiError{
+				status:  http.StatusBadRequest,
+				Code:    "42P10",
+				Message: "there is no unique or exclusion constraint matching the ON CONFLICT specification",
+			}
+		}
+	}
+
+	// Apply the whole batch or nothing, like a single INSERT statement
+	saved, savedSeq := t.snapshot(), t.seq
+
+	var written []map[string]interface{}
+	for _, payload := range payloads {
+		mode := ""
+		if req.upsert {
+			mode = "merge"
+			if req.ignoreDupes {
+				mode = "ignore"
+			}
+		}
+		row, err := t.insert(payload, conflict, mode)
+		if err != nil {
+			t.rows, t.seq = saved, savedSeq
+			return nil, err
+		}
+		if row != nil {
+			written = append(written, row)
+		}
+	}
+
+	return t.writeResponse(req, http.StatusCreated, written), nil
+}
+
+// insert adds one row, or with a conflict target merges it into (or skips
+// it for) the row it conflicts with. It returns the written row, nil when
+// skipped.
+func (t *table) insert(payload map[string]interface{}, conflict []string, mode string) (map[string]interface{}, error) {
+	values, err := t.decodeRow(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if conflict != nil {
+		if existing := t.find(conflict, values); existing != nil {
+			if mode == "ignore" {
+				return nil, nil
+			}
+			return t.apply(existing, values)
+		}
+	}
+
+	row := make(map[string]interface{}, len(t.Columns))
+	for i := range t.Columns {
+		column := &t.Columns[i]
+		if v, ok := values[column.Name]; ok {
+			row[column.Name] = v
+			if column.Type == Serial && v != nil && v.(int64) > t.seq {
+				// Keep generated ids clear of explicit ones
+				t.seq = v.(int64)
+			}
+			continue
+		}
+		if column.Type == Serial {
+			t.seq++
+			row[column.Name] = t.seq
+			continue
+		}
+		v, err := column.defaultValue()
+		if err != nil {
+			return nil, err
+		}
+		row[column.Name] = v
+	}
+
+	if err := t.check(row, nil); err != nil {
+		return nil, err
+	}
+	t.rows = append(t.rows, row)
+	return row, nil
+}
+
+func (t *table) update(req *request) (*response, error) {
+	payload, err := decodePayload(req.body)
+	if err != nil {
+		return nil, err
+	}
+	values, err := t.decodeRow(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := t.snapshot()
+
+	var written []map[string]interface{}
+	for _, row := range t.matching(req) {
+		if _, err := t.apply(row, values); err != nil {
+			t.rows = saved
+			return nil, err
+		}
+		written = append(written, row)
+	}
+
+	return t.writeResponse(req, http.StatusOK, written), nil
+}
+
+// apply sets values on an existing row, checking constraints first
+func (t *table) apply(row, values map[string]interface{}) (map[string]interface{}, error) {
+	updated := copyRow(row)
+	for column, v := range values {
+		updated[column] = v
+	}
+	if err := t.check(updated, row); err != nil {
+		return nil, err
+	}
+	for column, v := range values {
+		row[column] = v
+	}
+	return row, nil
+}
+
+func (t *table) remove(req *request) (*response, error) {
+	deleted := t.matching(req)
+
+	kept := t.rows[:0:0]
+	for _, row := range t.rows {
+		match := false
+		for _, d := range deleted {
+			if sameRow(row, d) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			kept = append(kept, row)
+		}
+	}
+	t.rows = kept
+
+	return t.writeResponse(req, http.StatusOK, deleted), nil
+}
+
+func (t *table) writeResponse(req *request, status int, rows []map[string]interface{}) *response {
+	if !req.representation {
+		if status != http.StatusCreated {
+			status = http.StatusNoContent
+		}
+		return &response{status: status}
+	}
+	return &response{
+		status:  status,
+		rows:    t.render(rows, req.columns),
+		hasBody: true,
+		single:  req.single,
+	}
+}
+
+// decodeRow validates a JSON object against the table and converts its values
+func (t *table) decodeRow(payload map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(payload))
+	for name, raw := range payload {
+		column, ok := t.column(name)
+		if !ok {
+			return nil, &apiError{
+				status:  http.StatusBadRequest,
+				Code:    "PGRST204",
+				Message: fmt.Sprintf("Could not find the '%s' column of '%s' in the schema cache", name, t.Name),
+			}
+		}
+		v, err := column.decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = v
+	}
+	return values, nil
+}
+
+// check enforces NOT NULL and unique constraints on row. self is the row
+// being replaced on update, which may keep its own values.
+func (t *table) check(row, self map[string]interface{}) error {
+	for _, column := range t.Columns {
+		if column.NotNull && row[column.Name] == nil {
+			return &apiError{
+				status:  http.StatusBadRequest,
+				Code:    "23502",
+				Message: fmt.Sprintf("null value in column %q of relation %q violates not-null constraint", column.Name, t.Name),
+			}
+		}
+	}
+
+	for _, c := range t.constraints() {
+		existing := t.find(c.columns, row)
+		if existing == nil || (self != nil && sameRow(existing, self)) {
+			continue
+		}
+		values := make([]string, len(c.columns))
+		for i, column := range c.columns {
+			values[i] = fmt.Sprint(encode(row[column]))
+		}
+		return &apiError{
+			status:  http.StatusConflict,
+			Code:    "23505",
+			Message: fmt.Sprintf("duplicate key value violates unique constraint %q", c.name),
+			Details: fmt.Sprintf("Key (%s)=(%s) already exists.", strings.Join(c.columns, ", "), strings.Join(values, ", ")),
+		}
+	}
+	return nil
+}
+
+// find returns the stored row whose columns equal values, if any. NULLs
+// never conflict, as in PostgreSQL.
+func (t *table) find(columns []string, values map[string]interface{}) map[string]interface{} {
+	for _, row := range t.rows {
+		match := true
+		for _, column := range columns {
+			a, b := row[column], values[column]
+			if a == nil || b == nil || compare(a, b) != 0 {
+				match = false
+				break
+			}
+		}
+		if match {
+			return row
+		}
+	}
+	return nil
+}
+
+func (t *table) constraintOn(columns []string) *constraint {
+	for _, c := range t.constraints() {
+		if sameColumns(c.columns, columns) {
+			return &c
+		}
+	}
+	return nil
+}
+
+// render serializes rows, keeping only columns when given
+func (t *table) render(rows []map[string]interface{}, columns []string) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		rendered := make(map[string]interface{})
+		if columns == nil {
+			for _, column := range t.Columns {
+				rendered[column.Name] = encode(row[column.Name])
+			}
+		} else {
+			for _, column := range columns {
+				rendered[column] = encode(row[column])
+			}
+		}
+		out[i] = rendered
+	}
+	return out
+}
+
+func decodePayloads(body []byte) ([]map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var payloads []map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		if err := dec.Decode(&payloads); err != nil {
+			return nil, invalidBody(err)
+		}
+		return payloads, nil
+	}
+	payload, err := decodePayload(body)
+	if err != nil {
+		return nil, err
+	}
+	return []map[string]interface{}{payload}, nil
+}
+
+func decodePayload(body []byte) (map[string]interface{}, error) {
+	var payload map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, invalidBody(err)
+	}
+	return payload, nil
+}
+
+// snapshot deep-copies the rows so a failed statement can be rolled back
+func (t *table) snapshot() []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = copyRow(row)
+	}
+	return rows
+}
+
+func copyRow(row map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		out[k] = v
+	}
+	return out
+}
+
+// sameRow reports whether a and b are the same stored row
+func sameRow(a, b map[string]interface{}) bool {
+	return reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, column := range a {
+		seen[column] = true
+	}
+	for _, column := range b {
+		if !seen[column] {
+			return false
+		}
+	}
+	return true
+}
+
+// apiError is a PostgREST error body
+type apiError struct {
+	status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	Hint    string `json:"hint"`
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = &apiError{status: http.StatusInternalServerError, Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(apiErr.status)
+	json.NewEncoder(w).Encode(apiErr)
+}
+
+func unknownTable(name string) *apiError {
+	return &apiError{
+		status:  http.StatusNotFound,
+		Code:    "42P01",
+		Message: fmt.Sprintf("relation \"public.%s\" does not exist", name),
+	}
+}
+
+func unknownColumn(tableName, column string) *apiError {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		Code:    "42703",
+		Message: fmt.Sprintf("column %s.%s does not exist", tableName, column),
+	}
+}
+
+func parseError(expr string) *apiError {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		Code:    "PGRST100",
+		Message: fmt.Sprintf("failed to parse filter (%s)", expr),
+	}
+}
+
+func invalidBody(err error) *apiError {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		Code:    "PGRST102",
+		Message: "Empty or invalid json",
+		Details: err.Error(),
+	}
+}
@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+)
+
+// captionLimit is the longest photo caption Telegram accepts, in characters
+const captionLimit = 1024
+
+// Bot answers updates: /start registers the sender, /pokemon <name> and,
+// in private chats, any other text look a Pokemon up. Inline queries list
+// Pokemon by name prefix.
+type Bot struct {
+	client      *Client
+	users       services.UserService
+	pokemon     services.PokemonService
+	inlineCache services.Cache
+}
+
+func NewBot(client *Client, users services.UserService, pokemon services.PokemonService) *Bot {
+	return &Bot{
+		client:      client,
+		users:       users,
+		pokemon:     pokemon,
+		inlineCache: services.NewMemoryCache(inlineCacheTTL, 1000),
+	}
+}
+
+// HandleUpdate replies to one update. Updates other than inline queries and
+// text messages from a user (edits, stickers, channel posts...) are ignored.
+func (b *Bot) HandleUpdate(update *Update) error {
+	if update.InlineQuery != nil {
+		return b.answerInline(update.InlineQuery)
+	}
+
+	msg := update.Message
+	if msg == nil || msg.From == nil || msg.From.IsBot || strings.TrimSpace(msg.Text) == "" {
+		return nil
+	}
+
+	lang := b.language(msg.From)
+	command, args := parseCommand(msg.Text)
+	switch command {
+	case "start":
+		return b.start(msg, lang)
+	case "pokemon":
+		if args == "" {
+			return b.reply(msg, i18n.T(lang, "telegram.pokemon_usage"))
+		}
+		return b.lookup(msg, args, lang)
+	case "":
+		// In groups the bot only answers commands, not every message
+		if msg.Chat.Type != ChatPrivate {
+			return nil
+		}
+		return b.lookup(msg, args, lang)
+	default:
+		return b.reply(msg, i18n.T(lang, "telegram.help"))
+	}
+}
+
+// parseCommand splits "/pokemon@SomeBot mr mime" into "pokemon" and
+// "mr mime". Text that isn't a command has an empty command and is
+// returned trimmed as args.
+func parseCommand(text string) (command, args string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", text
+	}
+	command, args, _ = strings.Cut(text[1:], " ")
+	command, _, _ = strings.Cut(command, "@")
+	return strings.ToLower(command), strings.TrimSpace(args)
+}
+
+// language is the sender's stored preference, else their Telegram client
+// language when supported
+func (b *Bot) language(from *User) string {
+	if user, err := b.users.GetUserByTelegramID(telegramID(from)); err == nil {
+		if lang, ok := i18n.Normalize(user.Language); ok {
+			return lang
+		}
+	}
+	if lang, ok := i18n.Normalize(from.LanguageCode); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+func (b *Bot) start(msg *Message, lang string) error {
+	from := msg.From
+	result, err := b.users.Register(telegramID(from), from.FirstName, from.LastName, from.Username)
+	if err != nil {
+		b.reply(msg, i18n.T(lang, "telegram.error"))
+		return err
+	}
+
+	key := "telegram.welcome"
+	if result.Exists {
+		key = "telegram.welcome_back"
+	}
+	return b.reply(msg, i18n.T(lang, key, from.FirstName))
+}
+
+// lookup answers with the Pokemon's sprite, captioned with the
+// telegram-html message
+func (b *Bot) lookup(msg *Message, query, lang string) error {
+	result, err := b.pokemon.GetPokemon(query, services.MessageOptions{Lang: lang, Channel: templates.TelegramHTML})
+	if err != nil {
+		b.reply(msg, i18n.T(lang, "telegram.error"))
+		return err
+	}
+	if !result.Found || result.Data == nil || result.Data.Sprite == "" {
+		_, err := b.client.SendMessage(msg.Chat.ID, result.Message, ParseModeHTML)
+		return err
+	}
+
+	if utf8.RuneCountInString(result.Message) > captionLimit {
+		if _, err := b.client.SendPhoto(msg.Chat.ID, result.Data.Sprite, "", ""); err != nil {
+			return err
+		}
+		_, err := b.client.SendMessage(msg.Chat.ID, result.Message, ParseModeHTML)
+		return err
+	}
+	_, err = b.client.SendPhoto(msg.Chat.ID, result.Data.Sprite, result.Message, ParseModeHTML)
+	return err
+}
+
+// reply sends plain text to the chat of msg
+func (b *Bot) reply(msg *Message, text string) error {
+	_, err := b.client.SendMessage(msg.Chat.ID, text, "")
+	return err
+}
+
+func telegramID(from *User) string {
+	return strconv.FormatInt(from.ID, 10)
+}
@@ -0,0 +1,176 @@
+package telegram_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/pokeapifake"
+	"github.com/yourusername/pokemon-chatbot-api/internal/repository"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/supabasefake"
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegram"
+	"github.com/yourusername/pokemon-chatbot-api/internal/telegramfake"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+)
+
+const testToken = "test-token"
+
+// startBot wires a bot to the fake Telegram, PokeAPI and Supabase, so
+// updates go through the real services end to end
+func startBot(t *testing.T, opts ...services.PokemonOption) (*telegram.Bot, *telegramfake.Server) {
+	t.Helper()
+	tg, sent := telegramfake.Start(testToken)
+	t.Cleanup(tg.Close)
+	sb, _ := supabasefake.Start()
+	t.Cleanup(sb.Close)
+	api, _, err := pokeapifake.Start(pokeapifake.Options{})
+	if err != nil {
+		t.Fatalf("start fake PokeAPI: %v", err)
+	}
+	t.Cleanup(api.Close)
+
+	users := services.NewUserService(repository.NewUserRepository(sb.URL, "key"))
+	opts = append([]services.PokemonOption{services.WithBaseURL(api.URL + "/api/v2")}, opts...)
+	pokemon := services.NewPokemonService(repository.NewSearchRepository(sb.URL, "key"), opts...)
+	return telegram.NewBot(telegram.NewClient(tg.URL, testToken), users, pokemon), sent
+}
+
+var ash = &telegram.User{ID: 42, FirstName: "Ash", Username: "ash"}
+
+func message(chatType, text string) *telegram.Update {
+	chatID := ash.ID
+	if chatType != telegram.ChatPrivate {
+		chatID = -100
+	}
+	return &telegram.Update{
+		UpdateID: 1,
+		Message: &telegram.Message{
+			MessageID: 1,
+			From:      ash,
+			Chat:      telegram.Chat{ID: chatID, Type: chatType},
+			Text:      text,
+		},
+	}
+}
+
+// handle sends an update and returns what the bot sent in reply
+func handle(t *testing.T, bot *telegram.Bot, fake *telegramfake.Server, update *telegram.Update) []telegramfake.Sent {
+	t.Helper()
+	fake.Reset()
+	if err := bot.HandleUpdate(update); err != nil {
+		t.Fatalf("HandleUpdate(%q): %v", update.Message.Text, err)
+	}
+	return fake.Sent()
+}
+
+func TestStartRegisters(t *testing.T) {
+	bot, fake := startBot(t)
+
+	sent := handle(t, bot, fake, message(telegram.ChatPrivate, "/start"))
+	want := i18n.T(i18n.Default, "telegram.welcome", "Ash")
+	if len(sent) != 1 || sent[0].Method != "sendMessage" || sent[0].Text != want {
+		t.Fatalf("first /start sent %+v, want %q", sent, want)
+	}
+	if sent[0].ChatID != ash.ID {
+		t.Errorf("chat id = %d, want %d", sent[0].ChatID, ash.ID)
+	}
+
+	sent = handle(t, bot, fake, message(telegram.ChatPrivate, "/start"))
+	want = i18n.T(i18n.Default, "telegram.welcome_back", "Ash")
+	if len(sent) != 1 || sent[0].Text != want {
+		t.Fatalf("second /start sent %+v, want %q", sent, want)
+	}
+}
+
+func TestPokemonWithoutName(t *testing.T) {
+	bot, fake := startBot(t)
+
+	for _, text := range []string{"/pokemon", "/pokemon   ", "/pokemon@PokemonBot"} {
+		sent := handle(t, bot, fake, message(telegram.ChatGroup, text))
+		want := i18n.T(i18n.Default, "telegram.pokemon_usage")
+		if len(sent) != 1 || sent[0].Method != "sendMessage" || sent[0].Text != want {
+			t.Errorf("%q sent %+v, want the usage", text, sent)
+		}
+	}
+}
+
+func TestPokemonCommand(t *testing.T) {
+	bot, fake := startBot(t)
+
+	// Commands are answered in groups too
+	sent := handle(t, bot, fake, message(telegram.ChatGroup, "/pokemon pikachu"))
+	if len(sent) != 1 || sent[0].Method != "sendPhoto" || sent[0].ChatID != -100 {
+		t.Fatalf("/pokemon pikachu sent %+v, want one photo to the group", sent)
+	}
+}
+
+func TestPrivateLookup(t *testing.T) {
+	bot, fake := startBot(t)
+
+	sent := handle(t, bot, fake, message(telegram.ChatPrivate, "pikachu"))
+	if len(sent) != 1 || sent[0].Method != "sendPhoto" {
+		t.Fatalf("lookup sent %+v, want one photo", sent)
+	}
+	photo := sent[0]
+	if !strings.HasSuffix(photo.Photo, "/25.png") {
+		t.Errorf("photo = %q, want Pikachu's artwork", photo.Photo)
+	}
+	if photo.ParseMode != telegram.ParseModeHTML || !strings.HasPrefix(photo.Caption, "<b>Pikachu</b> #25") {
+		t.Errorf("caption = %q (%s), want the telegram-html message", photo.Caption, photo.ParseMode)
+	}
+
+	// Unknown Pokemon get the not-found text, without a photo
+	sent = handle(t, bot, fake, message(telegram.ChatPrivate, "missingno"))
+	if len(sent) != 1 || sent[0].Method != "sendMessage" || !strings.Contains(sent[0].Text, "missingno") {
+		t.Errorf("unknown lookup sent %+v, want a not-found message", sent)
+	}
+}
+
+func TestPrivateLookupLongCaption(t *testing.T) {
+	// A telegram-html template whose message doesn't fit in a caption
+	dir := t.TempDir()
+	source := `{{define "pokemon.found"}}<b>{{.Name}}</b> ` + strings.Repeat("long ", 250) + `{{end}}` +
+		`{{define "pokemon.not_found"}}{{.Query}}{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, templates.TelegramHTML+".tmpl"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	set, err := templates.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	bot, fake := startBot(t, services.WithTemplates(set))
+
+	sent := handle(t, bot, fake, message(telegram.ChatPrivate, "pikachu"))
+	if len(sent) != 2 {
+		t.Fatalf("lookup sent %+v, want a photo and a message", sent)
+	}
+	if sent[0].Method != "sendPhoto" || sent[0].Caption != "" {
+		t.Errorf("first = %s with caption %q, want an uncaptioned photo", sent[0].Method, sent[0].Caption)
+	}
+	if sent[1].Method != "sendMessage" || sent[1].ParseMode != telegram.ParseModeHTML || len(sent[1].Text) <= 1024 {
+		t.Errorf("second = %s (%s, %d chars), want the full HTML message", sent[1].Method, sent[1].ParseMode, len(sent[1].Text))
+	}
+}
+
+func TestGroupMessagesIgnored(t *testing.T) {
+	bot, fake := startBot(t)
+
+	for _, chatType := range []string{telegram.ChatGroup, telegram.ChatSupergroup} {
+		if sent := handle(t, bot, fake, message(chatType, "pikachu")); len(sent) != 0 {
+			t.Errorf("%s text sent %+v, want nothing", chatType, sent)
+		}
+	}
+
+	// So are bots and updates without text
+	update := message(telegram.ChatPrivate, "pikachu")
+	update.Message.From = &telegram.User{ID: 7, IsBot: true, FirstName: "Bot"}
+	if sent := handle(t, bot, fake, update); len(sent) != 0 {
+		t.Errorf("message from a bot sent %+v, want nothing", sent)
+	}
+	if sent := handle(t, bot, fake, message(telegram.ChatPrivate, "  ")); len(sent) != 0 {
+		t.Errorf("blank message sent %+v, want nothing", sent)
+	}
+}
@@ -0,0 +1,144 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public Bot API endpoint
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Client calls Bot API methods as POST <baseURL>/bot<token>/<method>
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Bot API client. An empty baseURL uses
+// DefaultBaseURL; point it at telegramfake to run without Telegram.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// APIError is a request the Bot API answered with ok=false
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+type sendMessageRequest struct {
+	ChatID    int64  `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+type sendPhotoRequest struct {
+	ChatID    int64  `json:"chat_id"`
+	Photo     string `json:"photo"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+// SendMessage sends text to a chat, formatted per parseMode ("" for plain
+// text)
+func (c *Client) SendMessage(chatID int64, text, parseMode string) (*Message, error) {
+	var sent Message
+	err := c.call("sendMessage", sendMessageRequest{ChatID: chatID, Text: text, ParseMode: parseMode}, &sent)
+	if err != nil {
+		return nil, err
+	}
+	return &sent, nil
+}
+
+// SendPhoto sends a photo by URL with an optional caption
+func (c *Client) SendPhoto(chatID int64, photoURL, caption, parseMode string) (*Message, error) {
+	var sent Message
+	err := c.call("sendPhoto", sendPhotoRequest{ChatID: chatID, Photo: photoURL, Caption: caption, ParseMode: parseMode}, &sent)
+	if err != nil {
+		return nil, err
+	}
+	return &sent, nil
+}
+
+type answerInlineQueryRequest struct {
+	InlineQueryID string                   `json:"inline_query_id"`
+	Results       []InlineQueryResultPhoto `json:"results"`
+	CacheTime     int                      `json:"cache_time"`
+	IsPersonal    bool                     `json:"is_personal,omitempty"`
+	NextOffset    string                   `json:"next_offset"`
+}
+
+// AnswerInlineQuery answers an inline query. Telegram caches the answer
+// to the same query for cacheTime seconds, per user when personal is set;
+// an empty nextOffset means there are no more results.
+func (c *Client) AnswerInlineQuery(queryID string, results []InlineQueryResultPhoto, nextOffset string, cacheTime int, personal bool) error {
+	return c.call("answerInlineQuery", answerInlineQueryRequest{
+		InlineQueryID: queryID,
+		Results:       results,
+		CacheTime:     cacheTime,
+		IsPersonal:    personal,
+		NextOffset:    nextOffset,
+	}, nil)
+}
+
+// apiResponse is the envelope of every Bot API response
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+func (c *Client) call(method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	resp, err := c.httpClient.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The URL holds the token; keep it out of logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("telegram %s: status %d: %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		code := envelope.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &APIError{Method: method, Code: code, Description: envelope.Description}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
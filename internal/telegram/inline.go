@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+	"github.com/yourusername/pokemon-chatbot-api/internal/services"
+	"github.com/yourusername/pokemon-chatbot-api/internal/templates"
+)
+
+const (
+	// inlinePageSize is how many results one answer holds, Telegram's
+	// maximum
+	inlinePageSize = services.MaxPrefixResults
+	// inlineCacheTime is how long Telegram caches an answer, in seconds
+	inlineCacheTime = 300
+	// inlineCacheTTL is how long the bot keeps a built answer
+	inlineCacheTTL = 10 * time.Minute
+)
+
+// inlineAnswer is a built answer to a query, as cached
+type inlineAnswer struct {
+	Results    []InlineQueryResultPhoto `json:"results"`
+	NextOffset string                   `json:"next_offset"`
+}
+
+// answerInline answers "@bot pika" with the artwork of every Pokemon whose
+// name starts with "pika", a page at a time. Answers depend on the user's
+// language, so Telegram caches them per user.
+func (b *Bot) answerInline(q *InlineQuery) error {
+	lang := i18n.Default
+	if q.From != nil {
+		lang = b.language(q.From)
+	}
+	offset, err := strconv.Atoi(q.Offset)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	answer, err := b.inlineResults(strings.ToLower(strings.TrimSpace(q.Query)), offset, lang)
+	if err != nil {
+		// Answer anyway so the client stops waiting
+		b.client.AnswerInlineQuery(q.ID, []InlineQueryResultPhoto{}, "", 0, true)
+		return err
+	}
+	return b.client.AnswerInlineQuery(q.ID, answer.Results, answer.NextOffset, inlineCacheTime, true)
+}
+
+// inlineResults builds the page of results at offset, reusing one built
+// for the same query, offset and language within inlineCacheTTL
+func (b *Bot) inlineResults(query string, offset int, lang string) (*inlineAnswer, error) {
+	key := fmt.Sprintf("%s|%d|%s", lang, offset, query)
+	if cached, ok := b.inlineCache.Get(key); ok {
+		var answer inlineAnswer
+		if json.Unmarshal(cached, &answer) == nil {
+			return &answer, nil
+		}
+	}
+
+	answer := &inlineAnswer{Results: []InlineQueryResultPhoto{}}
+	// An empty query would list every Pokemon; show nothing until a letter
+	// is typed
+	if query != "" {
+		opts := services.MessageOptions{Lang: lang, Channel: templates.TelegramHTML}
+		page, err := b.pokemon.SearchByPrefix(query, offset, inlinePageSize, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			if result, ok := photoResult(&r); ok {
+				answer.Results = append(answer.Results, result)
+			}
+		}
+		if next := offset + inlinePageSize; next < page.Total {
+			answer.NextOffset = strconv.Itoa(next)
+		}
+	}
+
+	if body, err := json.Marshal(answer); err == nil {
+		b.inlineCache.Set(key, body)
+	}
+	return answer, nil
+}
+
+// photoResult is the inline card of a Pokemon: its artwork captioned with
+// the telegram-html message. Pokemon without artwork are left out.
+func photoResult(r *services.PokemonResponse) (InlineQueryResultPhoto, bool) {
+	data := r.Data
+	if data == nil || data.Sprite == "" {
+		return InlineQueryResultPhoto{}, false
+	}
+	result := InlineQueryResultPhoto{
+		Type:         "photo",
+		ID:           strconv.Itoa(data.ID),
+		PhotoURL:     data.Sprite,
+		ThumbnailURL: data.Sprite,
+		Title:        fmt.Sprintf("%s #%d", data.Name, data.ID),
+		Description:  data.Types,
+	}
+	// HTML can't be cut safely, so an overlong message sends the bare photo
+	if utf8.RuneCountInString(r.Message) <= captionLimit {
+		result.Caption = r.Message
+		result.ParseMode = ParseModeHTML
+	}
+	return result, true
+}
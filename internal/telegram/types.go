@@ -0,0 +1,72 @@
+// Package telegram is a minimal Telegram Bot API client and the bot that
+// answers webhook updates directly, as an alternative to running the bot
+// flow on Kata.
+package telegram
+
+// Update is an incoming update. Only messages and inline queries are
+// handled; other fields are ignored.
+type Update struct {
+	UpdateID    int64        `json:"update_id"`
+	Message     *Message     `json:"message,omitempty"`
+	InlineQuery *InlineQuery `json:"inline_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Date      int64  `json:"date"`
+	Text      string `json:"text,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+}
+
+type User struct {
+	ID           int64  `json:"id"`
+	IsBot        bool   `json:"is_bot"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// Chat types
+const (
+	ChatPrivate    = "private"
+	ChatGroup      = "group"
+	ChatSupergroup = "supergroup"
+	ChatChannel    = "channel"
+)
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// InlineQuery is what a user types after the bot's username in any chat.
+// Offset is the next_offset of the previous answer when scrolling.
+type InlineQuery struct {
+	ID       string `json:"id"`
+	From     *User  `json:"from"`
+	Query    string `json:"query"`
+	Offset   string `json:"offset"`
+	ChatType string `json:"chat_type,omitempty"`
+}
+
+// InlineQueryResultPhoto is an inline result showing a photo; picking it
+// sends the photo with its caption
+type InlineQueryResultPhoto struct {
+	Type         string `json:"type"`
+	ID           string `json:"id"`
+	PhotoURL     string `json:"photo_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Caption      string `json:"caption,omitempty"`
+	ParseMode    string `json:"parse_mode,omitempty"`
+}
+
+// Parse modes of sent text
+const (
+	ParseModeHTML       = "HTML"
+	ParseModeMarkdownV2 = "MarkdownV2"
+)
@@ -0,0 +1,188 @@
+// Package telegramfake is a local stand-in for the Telegram Bot API. It
+// accepts the sendMessage, sendPhoto and answerInlineQuery calls the bot
+// makes, checks the token and required parameters like Telegram does, and
+// records every call so it can be inspected instead of reaching a real
+// chat.
+package telegramfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sent is a message or inline answer the bot sent
+type Sent struct {
+	Method    string `json:"method"`
+	ChatID    int64  `json:"chat_id,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Photo     string `json:"photo,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+
+	// answerInlineQuery
+	InlineQueryID string         `json:"inline_query_id,omitempty"`
+	Results       []InlineResult `json:"results,omitempty"`
+	NextOffset    string         `json:"next_offset,omitempty"`
+	CacheTime     int            `json:"cache_time,omitempty"`
+	IsPersonal    bool           `json:"is_personal,omitempty"`
+}
+
+// InlineResult is the part of an inline query result the fake checks
+type InlineResult struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	PhotoURL string `json:"photo_url,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+}
+
+// Server serves /bot<token>/<method>. It implements http.Handler.
+type Server struct {
+	token string
+
+	mu     sync.Mutex
+	sent   []Sent
+	nextID int64
+
+	// OnSend, when set, is called with every recorded message
+	OnSend func(Sent)
+}
+
+// New creates a server accepting calls made with token
+func New(token string) *Server {
+	return &Server{token: token, nextID: 1}
+}
+
+// Start runs a server on a random local port. Its URL is the base URL for
+// telegram.NewClient; close it when done.
+func Start(token string) (*httptest.Server, *Server) {
+	s := New(token)
+	return httptest.NewServer(s), s
+}
+
+// Sent returns the messages sent so far, oldest first
+func (s *Server) Sent() []Sent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Sent(nil), s.sent...)
+}
+
+// Reset forgets the sent messages
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if !ok || !strings.HasPrefix(r.URL.Path, "/bot") {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if token != s.token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusBadRequest, "Bad Request: only POST with a JSON body is supported")
+		return
+	}
+
+	var msg Sent
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: invalid JSON")
+		return
+	}
+	msg.Method = method
+
+	switch method {
+	case "sendMessage":
+		if msg.Text == "" {
+			writeError(w, http.StatusBadRequest, "Bad Request: message text is empty")
+			return
+		}
+	case "sendPhoto":
+		if msg.Photo == "" {
+			writeError(w, http.StatusBadRequest, "Bad Request: there is no photo in the request")
+			return
+		}
+		if len([]rune(msg.Caption)) > 1024 {
+			writeError(w, http.StatusBadRequest, "Bad Request: message caption is too long")
+			return
+		}
+	case "answerInlineQuery":
+		if msg.InlineQueryID == "" {
+			writeError(w, http.StatusBadRequest, "Bad Request: inline_query_id is empty")
+			return
+		}
+		if len(msg.Results) > 50 {
+			writeError(w, http.StatusBadRequest, "Bad Request: too many inline query results specified")
+			return
+		}
+		ids := make(map[string]bool, len(msg.Results))
+		for _, r := range msg.Results {
+			if r.ID == "" || ids[r.ID] {
+				writeError(w, http.StatusBadRequest, "Bad Request: RESULT_ID_DUPLICATE")
+				return
+			}
+			ids[r.ID] = true
+		}
+		s.record(msg)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "result": true})
+		return
+	default:
+		writeError(w, http.StatusNotFound, "Not Found: method not found")
+		return
+	}
+	if msg.ChatID == 0 {
+		writeError(w, http.StatusBadRequest, "Bad Request: chat_id is empty")
+		return
+	}
+
+	id := s.record(msg)
+	result := map[string]interface{}{
+		"message_id": id,
+		"date":       time.Now().Unix(),
+		"chat":       map[string]interface{}{"id": msg.ChatID, "type": "private"},
+	}
+	if msg.Text != "" {
+		result["text"] = msg.Text
+	}
+	if msg.Caption != "" {
+		result["caption"] = msg.Caption
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "result": result})
+}
+
+// record stores a call and returns the message id it gets
+func (s *Server) record(msg Sent) int64 {
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.sent = append(s.sent, msg)
+	onSend := s.OnSend
+	s.mu.Unlock()
+	if onSend != nil {
+		onSend(msg)
+	}
+	return id
+}
+
+func writeError(w http.ResponseWriter, status int, description string) {
+	writeJSON(w, status, map[string]interface{}{
+		"ok":          false,
+		"error_code":  status,
+		"description": description,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,237 @@
+// Package templates renders the bot's reply messages with text/template,
+// one template file per channel. Every value a template prints is escaped
+// for the channel's format (Telegram MarkdownV2, HTML, ...) unless piped
+// through raw; the literal markup of the template is left alone.
+//
+// Templates define one named template per message ("pokemon.found", ...)
+// and translate text with the t function, which formats an i18n catalog
+// entry in the language of the request:
+//
+//	{{define "pokemon.found"}}<b>{{.Name}}</b> {{t "pokemon.weak_to" .Weaknesses}}{{end}}
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/i18n"
+)
+
+// Channels a message can be rendered for
+const (
+	Plain            = "plain"
+	Kata             = "kata"
+	TelegramMarkdown = "telegram-markdown"
+	TelegramHTML     = "telegram-html"
+)
+
+// Channels lists every channel, Plain first
+var Channels = []string{Plain, Kata, TelegramMarkdown, TelegramHTML}
+
+// Messages each channel template must define
+const (
+	PokemonFound    = "pokemon.found"
+	PokemonNotFound = "pokemon.not_found"
+)
+
+var messages = []string{PokemonFound, PokemonNotFound}
+
+// ErrInvalidTemplate wraps template parse and validation errors
+var ErrInvalidTemplate = errors.New("invalid template")
+
+//go:embed defaults/*.tmpl
+var defaults embed.FS
+
+// escapers escape a printed value for each channel
+var escapers = map[string]func(string) string{
+	Plain: func(s string) string { return s },
+	// Kata.ai shows message text as is
+	Kata:             func(s string) string { return s },
+	TelegramMarkdown: escapeMarkdownV2,
+	TelegramHTML:     html.EscapeString,
+}
+
+// markdownV2Special are the characters Telegram's MarkdownV2 requires
+// escaping outside of entities
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+func escapeMarkdownV2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownV2Special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Set holds the parsed template of every channel
+type Set struct {
+	templates map[string]*template.Template
+	sources   map[string]string
+}
+
+// Default returns the built-in templates
+func Default() *Set {
+	set, err := Load("")
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// Load reads the built-in templates, replacing each channel for which dir
+// holds a <channel>.tmpl file. An empty dir uses only the built-ins.
+func Load(dir string) (*Set, error) {
+	set := &Set{
+		templates: make(map[string]*template.Template, len(Channels)),
+		sources:   make(map[string]string, len(Channels)),
+	}
+	for _, channel := range Channels {
+		name := channel + ".tmpl"
+		source, err := defaults.ReadFile("defaults/" + name)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			custom, err := os.ReadFile(filepath.Join(dir, name))
+			switch {
+			case err == nil:
+				source = custom
+			case !errors.Is(err, os.ErrNotExist):
+				return nil, err
+			}
+		}
+
+		tmpl, err := Parse(channel, string(source))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		set.templates[channel] = tmpl
+		set.sources[channel] = string(source)
+	}
+	return set, nil
+}
+
+// Source returns the template text of a channel
+func (s *Set) Source(channel string) (string, bool) {
+	source, ok := s.sources[channel]
+	return source, ok
+}
+
+// Render executes a message of a channel's template in lang
+func (s *Set) Render(channel, message, lang string, data interface{}) (string, error) {
+	tmpl, ok := s.templates[channel]
+	if !ok {
+		return "", fmt.Errorf("unknown channel %q", channel)
+	}
+	return Execute(tmpl, message, lang, data)
+}
+
+// IsChannel reports whether channel is known
+func IsChannel(channel string) bool {
+	_, ok := escapers[channel]
+	return ok
+}
+
+// Parse parses and validates a channel template, making every printed
+// value escape for the channel. Errors wrap ErrInvalidTemplate.
+func Parse(channel, source string) (*template.Template, error) {
+	escape, ok := escapers[channel]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown channel %q", ErrInvalidTemplate, channel)
+	}
+
+	tmpl, err := template.New(channel).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"escape": func(v interface{}) string { return escape(fmt.Sprint(v)) },
+			"raw":    func(v interface{}) interface{} { return v },
+			"t":      translate(i18n.Default),
+		}).
+		Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			autoEscape(t.Tree.Root)
+		}
+	}
+	for _, message := range messages {
+		if tmpl.Lookup(message) == nil {
+			return nil, fmt.Errorf("%w: missing {{define %q}}", ErrInvalidTemplate, message)
+		}
+	}
+	return tmpl, nil
+}
+
+// Execute runs one message of a parsed template with t bound to lang
+func Execute(tmpl *template.Template, message, lang string, data interface{}) (string, error) {
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	clone.Funcs(template.FuncMap{"t": translate(lang)})
+
+	var buf bytes.Buffer
+	if err := clone.ExecuteTemplate(&buf, message, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func translate(lang string) func(string, ...interface{}) string {
+	return func(key string, args ...interface{}) string {
+		return i18n.T(lang, key, args...)
+	}
+}
+
+// autoEscape appends "| escape" to every action that prints a value, the
+// way html/template rewrites its pipelines. Variable declarations print
+// nothing and pipelines ending in raw are left as they are.
+func autoEscape(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			autoEscape(child)
+		}
+	case *parse.ActionNode:
+		pipe := n.Pipe
+		if len(pipe.Decl) > 0 || len(pipe.Cmds) == 0 {
+			return
+		}
+		last := pipe.Cmds[len(pipe.Cmds)-1]
+		if id, ok := last.Args[0].(*parse.IdentifierNode); ok && (id.Ident == "raw" || id.Ident == "escape") {
+			return
+		}
+		escape := parse.NewIdentifier("escape").SetPos(n.Pos)
+		pipe.Cmds = append(pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{escape},
+		})
+	case *parse.IfNode:
+		autoEscape(n.List)
+		autoEscape(n.ElseList)
+	case *parse.RangeNode:
+		autoEscape(n.List)
+		autoEscape(n.ElseList)
+	case *parse.WithNode:
+		autoEscape(n.List)
+		autoEscape(n.ElseList)
+	}
+}
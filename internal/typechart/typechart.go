@@ -0,0 +1,272 @@
+// Package typechart holds the Pokemon type effectiveness chart and computes
+// matchups for single and dual types. Charts differ between generations:
+// Dark and Steel arrived in generation 2, Fairy in generation 6, and a few
+// multipliers changed along the way.
+package typechart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Types in PokeAPI's order
+const (
+	Normal   = "normal"
+	Fighting = "fighting"
+	Flying   = "flying"
+	Poison   = "poison"
+	Ground   = "ground"
+	Rock     = "rock"
+	Bug      = "bug"
+	Ghost    = "ghost"
+	Steel    = "steel"
+	Fire     = "fire"
+	Water    = "water"
+	Grass    = "grass"
+	Electric = "electric"
+	Psychic  = "psychic"
+	Ice      = "ice"
+	Dragon   = "dragon"
+	Dark     = "dark"
+	Fairy    = "fairy"
+)
+
+// LatestGeneration is the newest generation the charts know about. Every
+// generation from 6 on shares the same chart.
+const LatestGeneration = 9
+
+var allTypes = []string{
+	Normal, Fighting, Flying, Poison, Ground, Rock, Bug, Ghost, Steel,
+	Fire, Water, Grass, Electric, Psychic, Ice, Dragon, Dark, Fairy,
+}
+
+// modern lists the generation 6+ multipliers that differ from 1, by
+// attacking type then defending type
+var modern = map[string]map[string]float64{
+	Normal:   {Rock: 0.5, Ghost: 0, Steel: 0.5},
+	Fighting: {Normal: 2, Flying: 0.5, Poison: 0.5, Rock: 2, Bug: 0.5, Ghost: 0, Steel: 2, Psychic: 0.5, Ice: 2, Dark: 2, Fairy: 0.5},
+	Flying:   {Fighting: 2, Rock: 0.5, Bug: 2, Steel: 0.5, Grass: 2, Electric: 0.5},
+	Poison:   {Poison: 0.5, Ground: 0.5, Rock: 0.5, Ghost: 0.5, Steel: 0, Grass: 2, Fairy: 2},
+	Ground:   {Flying: 0, Poison: 2, Rock: 2, Bug: 0.5, Steel: 2, Fire: 2, Grass: 0.5, Electric: 2},
+	Rock:     {Fighting: 0.5, Flying: 2, Ground: 0.5, Bug: 2, Steel: 0.5, Fire: 2, Ice: 2},
+	Bug:      {Fighting: 0.5, Flying: 0.5, Poison: 0.5, Ghost: 0.5, Steel: 0.5, Fire: 0.5, Grass: 2, Psychic: 2, Dark: 2, Fairy: 0.5},
+	Ghost:    {Normal: 0, Ghost: 2, Psychic: 2, Dark: 0.5},
+	Steel:    {Rock: 2, Steel: 0.5, Fire: 0.5, Water: 0.5, Electric: 0.5, Ice: 2, Fairy: 2},
+	Fire:     {Rock: 0.5, Bug: 2, Steel: 2, Fire: 0.5, Water: 0.5, Grass: 2, Ice: 2, Dragon: 0.5},
+	Water:    {Ground: 2, Rock: 2, Fire: 2, Water: 0.5, Grass: 0.5, Dragon: 0.5},
+	Grass:    {Flying: 0.5, Poison: 0.5, Ground: 2, Rock: 2, Bug: 0.5, Steel: 0.5, Fire: 0.5, Water: 2, Grass: 0.5, Dragon: 0.5},
+	Electric: {Flying: 2, Ground: 0, Water: 2, Grass: 0.5, Electric: 0.5, Dragon: 0.5},
+	Psychic:  {Fighting: 2, Poison: 2, Steel: 0.5, Psychic: 0.5, Dark: 0},
+	Ice:      {Flying: 2, Ground: 2, Steel: 0.5, Fire: 0.5, Water: 0.5, Grass: 2, Ice: 0.5, Dragon: 2},
+	Dragon:   {Steel: 0.5, Dragon: 2, Fairy: 0},
+	Dark:     {Fighting: 0.5, Ghost: 2, Psychic: 2, Dark: 0.5, Fairy: 0.5},
+	Fairy:    {Fighting: 2, Poison: 0.5, Steel: 0.5, Fire: 0.5, Dragon: 2, Dark: 2},
+}
+
+// override changes one multiplier of an older chart
+type override struct {
+	attack, defend string
+	multiplier     float64
+}
+
+// Differences of generations 2-5 from the modern chart, before Fairy
+var gen2Overrides = []override{
+	{Ghost, Steel, 0.5},
+	{Dark, Steel, 0.5},
+}
+
+// Differences of generation 1 from the generation 2-5 chart
+var gen1Overrides = []override{
+	{Bug, Poison, 2},
+	{Poison, Bug, 2},
+	{Ghost, Psychic, 0}, // a programming error made Psychic immune
+	{Ice, Fire, 1},
+}
+
+// Chart is the type chart of one generation
+type Chart struct {
+	Generation int
+	types      []string
+	multiplier map[string]map[string]float64
+}
+
+var charts = map[int]*Chart{
+	1: build(1, []string{Dark, Steel, Fairy}, gen2Overrides, gen1Overrides),
+	2: build(2, []string{Fairy}, gen2Overrides),
+	6: build(6, nil),
+}
+
+func build(generation int, missing []string, overrides ...[]override) *Chart {
+	c := &Chart{Generation: generation, multiplier: make(map[string]map[string]float64)}
+	skip := make(map[string]bool)
+	for _, t := range missing {
+		skip[t] = true
+	}
+	for _, t := range allTypes {
+		if !skip[t] {
+			c.types = append(c.types, t)
+		}
+	}
+
+	for _, attack := range c.types {
+		c.multiplier[attack] = make(map[string]float64)
+		for defend, m := range modern[attack] {
+			if !skip[defend] {
+				c.multiplier[attack][defend] = m
+			}
+		}
+	}
+	for _, list := range overrides {
+		for _, o := range list {
+			switch {
+			case skip[o.attack] || skip[o.defend]:
+			case o.multiplier == 1:
+				delete(c.multiplier[o.attack], o.defend)
+			default:
+				c.multiplier[o.attack][o.defend] = o.multiplier
+			}
+		}
+	}
+	return c
+}
+
+// Latest returns the current chart
+func Latest() *Chart {
+	return charts[6]
+}
+
+// ForGeneration returns the chart used in a generation, 1 to
+// LatestGeneration. Zero means the latest.
+func ForGeneration(generation int) (*Chart, error) {
+	switch {
+	case generation == 0 || (generation >= 6 && generation <= LatestGeneration):
+		return charts[6], nil
+	case generation >= 2 && generation <= 5:
+		return charts[2], nil
+	case generation == 1:
+		return charts[1], nil
+	}
+	return nil, fmt.Errorf("generation must be between 1 and %d", LatestGeneration)
+}
+
+// Types lists the types that exist in the chart's generation
+func (c *Chart) Types() []string {
+	return append([]string(nil), c.types...)
+}
+
+// Has reports whether a type exists in the chart's generation
+func (c *Chart) Has(t string) bool {
+	_, ok := c.multiplier[strings.ToLower(t)]
+	return ok
+}
+
+// Effectiveness returns the multiplier of an attacking type against one
+// defending type
+func (c *Chart) Effectiveness(attack, defend string) float64 {
+	row, ok := c.multiplier[strings.ToLower(attack)]
+	if !ok {
+		return 1
+	}
+	if m, ok := row[strings.ToLower(defend)]; ok {
+		return m
+	}
+	return 1
+}
+
+// Against returns the multiplier of an attacking type against a Pokemon of
+// one or two types; dual types multiply
+func (c *Chart) Against(attack string, defending ...string) float64 {
+	m := 1.0
+	for _, d := range defending {
+		m *= c.Effectiveness(attack, d)
+	}
+	return m
+}
+
+// Matchup is an attacking (or defending) type with its multiplier
+type Matchup struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Matchups groups every attacking type by how well it hits a defender
+type Matchups struct {
+	Weaknesses  []Matchup `json:"weaknesses"`
+	Resistances []Matchup `json:"resistances"`
+	Immunities  []Matchup `json:"immunities"`
+}
+
+// Defending computes the matchups of a Pokemon of the given types. Each
+// group is sorted by strength, strongest effect first, then chart order.
+func (c *Chart) Defending(types ...string) Matchups {
+	m := Matchups{
+		Weaknesses:  []Matchup{},
+		Resistances: []Matchup{},
+		Immunities:  []Matchup{},
+	}
+	for _, attack := range c.types {
+		multiplier := c.Against(attack, types...)
+		matchup := Matchup{Type: attack, Multiplier: multiplier}
+		switch {
+		case multiplier == 0:
+			m.Immunities = append(m.Immunities, matchup)
+		case multiplier > 1:
+			m.Weaknesses = append(m.Weaknesses, matchup)
+		case multiplier < 1:
+			m.Resistances = append(m.Resistances, matchup)
+		}
+	}
+	sort.SliceStable(m.Weaknesses, func(i, j int) bool {
+		return m.Weaknesses[i].Multiplier > m.Weaknesses[j].Multiplier
+	})
+	sort.SliceStable(m.Resistances, func(i, j int) bool {
+		return m.Resistances[i].Multiplier < m.Resistances[j].Multiplier
+	})
+	return m
+}
+
+// Relations describes one type on offense and defense
+type Relations struct {
+	Type      string   `json:"type"`
+	Attacking Offense  `json:"attacking"`
+	Defending Matchups `json:"defending"`
+}
+
+// Offense lists the types an attacking type hits for double, half and no
+// damage
+type Offense struct {
+	SuperEffective   []string `json:"super_effective"`
+	NotVeryEffective []string `json:"not_very_effective"`
+	NoEffect         []string `json:"no_effect"`
+}
+
+// Relations returns how a type fares attacking and defending. ok is false
+// when the type doesn't exist in the chart's generation.
+func (c *Chart) Relations(t string) (Relations, bool) {
+	t = strings.ToLower(t)
+	if !c.Has(t) {
+		return Relations{}, false
+	}
+
+	r := Relations{
+		Type: t,
+		Attacking: Offense{
+			SuperEffective:   []string{},
+			NotVeryEffective: []string{},
+			NoEffect:         []string{},
+		},
+		Defending: c.Defending(t),
+	}
+	for _, defend := range c.types {
+		switch m := c.Effectiveness(t, defend); {
+		case m == 0:
+			r.Attacking.NoEffect = append(r.Attacking.NoEffect, defend)
+		case m > 1:
+			r.Attacking.SuperEffective = append(r.Attacking.SuperEffective, defend)
+		case m < 1:
+			r.Attacking.NotVeryEffective = append(r.Attacking.NotVeryEffective, defend)
+		}
+	}
+	return r, true
+}
@@ -0,0 +1,104 @@
+package typechart_test
+
+import (
+	"testing"
+
+	"github.com/yourusername/pokemon-chatbot-api/internal/typechart"
+)
+
+func chart(t *testing.T, generation int) *typechart.Chart {
+	t.Helper()
+	c, err := typechart.ForGeneration(generation)
+	if err != nil {
+		t.Fatalf("ForGeneration(%d): %v", generation, err)
+	}
+	return c
+}
+
+func TestForGeneration(t *testing.T) {
+	// Each generation maps to the chart it played with
+	tests := []struct {
+		generation, chart, types int
+	}{
+		{0, 6, 18},
+		{1, 1, 15},
+		{2, 2, 17},
+		{5, 2, 17},
+		{6, 6, 18},
+		{typechart.LatestGeneration, 6, 18},
+	}
+	for _, tt := range tests {
+		c := chart(t, tt.generation)
+		if c.Generation != tt.chart || len(c.Types()) != tt.types {
+			t.Errorf("ForGeneration(%d) = chart %d with %d types, want %d with %d",
+				tt.generation, c.Generation, len(c.Types()), tt.chart, tt.types)
+		}
+	}
+
+	for _, generation := range []int{-1, typechart.LatestGeneration + 1} {
+		if _, err := typechart.ForGeneration(generation); err == nil {
+			t.Errorf("ForGeneration(%d) succeeded", generation)
+		}
+	}
+}
+
+func TestEffectivenessOverrides(t *testing.T) {
+	tests := []struct {
+		name           string
+		attack, defend string
+		// multipliers in generation 1, 2-5 and 6+
+		gen1, gen2, gen6 float64
+	}{
+		{"ghost on steel resisted before 6", typechart.Ghost, typechart.Steel, 1, 0.5, 1},
+		{"dark on steel resisted before 6", typechart.Dark, typechart.Steel, 1, 0.5, 1},
+		{"bug on poison doubled in 1", typechart.Bug, typechart.Poison, 2, 0.5, 0.5},
+		{"poison on bug doubled in 1", typechart.Poison, typechart.Bug, 2, 1, 1},
+		{"psychic immune to ghost in 1", typechart.Ghost, typechart.Psychic, 0, 2, 2},
+		{"ice neutral on fire in 1", typechart.Ice, typechart.Fire, 1, 0.5, 0.5},
+		{"dragon on fairy only from 6", typechart.Dragon, typechart.Fairy, 1, 1, 0},
+		{"unchanged", typechart.Water, typechart.Fire, 2, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range []struct {
+				generation int
+				multiplier float64
+			}{{1, tt.gen1}, {2, tt.gen2}, {6, tt.gen6}} {
+				if got := chart(t, want.generation).Effectiveness(tt.attack, tt.defend); got != want.multiplier {
+					t.Errorf("gen %d %s on %s = %v, want %v", want.generation, tt.attack, tt.defend, got, want.multiplier)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingTypes(t *testing.T) {
+	gen1 := chart(t, 1)
+	for _, missing := range []string{typechart.Dark, typechart.Steel, typechart.Fairy} {
+		if gen1.Has(missing) {
+			t.Errorf("generation 1 has %s", missing)
+		}
+		if _, ok := gen1.Relations(missing); ok {
+			t.Errorf("generation 1 has relations for %s", missing)
+		}
+	}
+	if chart(t, 2).Has(typechart.Fairy) || !chart(t, 2).Has(typechart.Steel) {
+		t.Error("generations 2-5 should have Steel and no Fairy")
+	}
+}
+
+func TestDefending(t *testing.T) {
+	// Dual types multiply: Gyarados is 4x weak to Electric and immune to Ground
+	m := chart(t, 0).Defending(typechart.Water, typechart.Flying)
+	if len(m.Weaknesses) == 0 || m.Weaknesses[0] != (typechart.Matchup{Type: typechart.Electric, Multiplier: 4}) {
+		t.Errorf("weaknesses = %+v, want Electric 4x first", m.Weaknesses)
+	}
+	if len(m.Immunities) != 1 || m.Immunities[0].Type != typechart.Ground {
+		t.Errorf("immunities = %+v, want Ground", m.Immunities)
+	}
+	for _, r := range m.Resistances {
+		if r.Multiplier >= 1 {
+			t.Errorf("resistance %+v isn't below 1", r)
+		}
+	}
+}